@@ -1,13 +1,31 @@
 package commands
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+)
+
+var (
+	ErrWrongExpectedVersion = errors.New("wrong expected version")
+	ErrStreamAlreadyExists  = errors.New("stream already exists")
+	ErrStreamNotFound       = errors.New("stream not found")
+)
+
 type CommandEvent[T any] struct {
 	Type    int
 	Payload T
 }
 
 type Command[T any] struct {
-	Type   int
-	Events []CommandEvent[T]
+	events.Metadata
+	ID              string
+	Type            int
+	Events          []CommandEvent[T]
+	ExpectedVersion *int
+	NoStream        bool
+	StreamExists    bool
 }
 
 func NewCommandEvent[T any](eType int, payload T) CommandEvent[T] {
@@ -17,3 +35,46 @@ func NewCommandEvent[T any](eType int, payload T) CommandEvent[T] {
 func NewCommand[T any](cType int, events []CommandEvent[T]) Command[T] {
 	return Command[T]{Events: events, Type: cType}
 }
+
+func (c Command[T]) WithID(id string) Command[T] {
+	c.ID = id
+	return c
+}
+
+func (c Command[T]) WithMetadata(metadata events.Metadata) Command[T] {
+	c.Metadata = metadata
+	return c
+}
+
+func (c Command[T]) WithExpectedVersion(version int) Command[T] {
+	c.ExpectedVersion = &version
+	return c
+}
+
+func (c Command[T]) WithNoStream() Command[T] {
+	c.NoStream = true
+	return c
+}
+
+func (c Command[T]) WithStreamExists() Command[T] {
+	c.StreamExists = true
+	return c
+}
+
+func (c Command[T]) CheckPreconditions(version int) error {
+	if c.NoStream && version > 0 {
+		return fmt.Errorf("%w: current version %d", ErrStreamAlreadyExists, version)
+	}
+	if c.StreamExists && version == 0 {
+		return ErrStreamNotFound
+	}
+	if c.ExpectedVersion != nil && *c.ExpectedVersion != version {
+		return fmt.Errorf(
+			"%w: expected version %d, current version %d",
+			ErrWrongExpectedVersion,
+			*c.ExpectedVersion,
+			version,
+		)
+	}
+	return nil
+}
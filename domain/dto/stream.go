@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+)
+
+type StreamEvent[T any] struct {
+	Position      string          `json:"position"`
+	AggregateID   string          `json:"aggregate_id"`
+	AggregateType string          `json:"aggregate_type,omitempty"`
+	TransactionID string          `json:"transaction_id"`
+	CommandType   int             `json:"command_type"`
+	Type          int             `json:"type"`
+	Version       int             `json:"version"`
+	Payload       T               `json:"payload"`
+	Metadata      events.Metadata `json:"metadata"`
+	CreatedAt     *time.Time      `json:"created_at,omitempty"`
+}
+
+func NewStreamEvent[T any](event events.StreamEvent[T]) StreamEvent[T] {
+	return StreamEvent[T]{
+		Position:      event.Position.String(),
+		AggregateID:   event.AggregateID.String(),
+		AggregateType: event.AggregateType,
+		TransactionID: event.TransactionID.String(),
+		CommandType:   event.CommandType,
+		Type:          event.Type,
+		Version:       event.Version,
+		Payload:       event.Payload,
+		Metadata:      event.Metadata,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+type StreamMessage struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
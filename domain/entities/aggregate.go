@@ -9,6 +9,7 @@ import (
 
 type AggregateReader[T any] interface {
 	ID() uuid.UUID
+	Type() string
 	Cap() int
 	Version() int
 	BaseVersion() int
@@ -32,6 +33,7 @@ type AggregateProvider[T, S, P, K any] interface {
 
 type Aggregate[T, S any] struct {
 	id            uuid.UUID
+	aggregateType string
 	cap           int
 	version       int
 	baseVersion   int
@@ -63,6 +65,15 @@ func (a *Aggregate[T, S]) ID() uuid.UUID {
 	return a.id
 }
 
+func (a *Aggregate[T, S]) Type() string {
+	return a.aggregateType
+}
+
+func (a *Aggregate[T, S]) WithType(aggregateType string) *Aggregate[T, S] {
+	a.aggregateType = aggregateType
+	return a
+}
+
 func (a *Aggregate[T, S]) Cap() int {
 	return a.cap
 }
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownAggregateType   = errors.New("unknown aggregate type")
+	ErrDuplicateAggregateType = errors.New("aggregate type is already registered")
+)
+
+type ProviderFactory[T, S, P, K any] func(id uuid.UUID) AggregateProvider[T, S, P, K]
+
+type AggregateRegistry[T, S, P, K any] struct {
+	factories map[string]ProviderFactory[T, S, P, K]
+}
+
+func NewAggregateRegistry[T, S, P, K any]() *AggregateRegistry[T, S, P, K] {
+	return &AggregateRegistry[T, S, P, K]{
+		factories: make(map[string]ProviderFactory[T, S, P, K]),
+	}
+}
+
+func (r *AggregateRegistry[T, S, P, K]) Register(
+	aggregateType string,
+	factory ProviderFactory[T, S, P, K],
+) error {
+	if _, ok := r.factories[aggregateType]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateAggregateType, aggregateType)
+	}
+	r.factories[aggregateType] = factory
+	return nil
+}
+
+func (r *AggregateRegistry[T, S, P, K]) Provider(
+	aggregateType string,
+	id uuid.UUID,
+) (AggregateProvider[T, S, P, K], error) {
+	factory, ok := r.factories[aggregateType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAggregateType, aggregateType)
+	}
+	return factory(id), nil
+}
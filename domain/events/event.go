@@ -6,7 +6,15 @@ import (
 	"github.com/google/uuid"
 )
 
+type Metadata struct {
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	CausationID   string            `json:"causation_id,omitempty"`
+	Actor         string            `json:"actor,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
 type Event[T any] struct {
+	Metadata
 	AggregateID   uuid.UUID
 	TransactionID uuid.UUID
 	CommandType   int
@@ -17,9 +25,19 @@ type Event[T any] struct {
 }
 
 type IntegrationEvent[T any] struct {
-	ID      string `json:"id"`
-	Type    int    `json:"type"`
-	Payload T      `json:"payload"`
+	ID       string   `json:"id"`
+	Type     int      `json:"type"`
+	Payload  T        `json:"payload"`
+	Metadata Metadata `json:"metadata"`
+}
+
+func NewMetadata(correlationID, causationID, actor string, headers map[string]string) Metadata {
+	return Metadata{
+		CorrelationID: correlationID,
+		CausationID:   causationID,
+		Actor:         actor,
+		Headers:       headers,
+	}
 }
 
 func NewEvent[T any](
@@ -38,6 +56,11 @@ func NewEvent[T any](
 	}
 }
 
+func (e Event[T]) WithMetadata(metadata Metadata) Event[T] {
+	e.Metadata = metadata
+	return e
+}
+
 func NewIntegrationEvent[T any](
 	id uuid.UUID,
 	evType int,
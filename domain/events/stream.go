@@ -0,0 +1,62 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidPosition = errors.New("invalid position")
+
+type Position struct {
+	SequenceID int64
+	Version    int
+}
+
+func ParsePosition(value string) (Position, error) {
+	sequenceID, version, found := strings.Cut(value, "-")
+	if !found {
+		return Position{}, fmt.Errorf("%w: %q", ErrInvalidPosition, value)
+	}
+	parsedSequenceID, err := strconv.ParseInt(sequenceID, 10, 64)
+	if err != nil {
+		return Position{}, fmt.Errorf("%w: %q", ErrInvalidPosition, value)
+	}
+	parsedVersion, err := strconv.Atoi(version)
+	if err != nil {
+		return Position{}, fmt.Errorf("%w: %q", ErrInvalidPosition, value)
+	}
+	return Position{SequenceID: parsedSequenceID, Version: parsedVersion}, nil
+}
+
+func (p Position) String() string {
+	return strconv.FormatInt(p.SequenceID, 10) + "-" + strconv.Itoa(p.Version)
+}
+
+func (p Position) After(other Position) bool {
+	return p.SequenceID > other.SequenceID || p.SequenceID == other.SequenceID && p.Version > other.Version
+}
+
+type Filter struct {
+	AggregateIDs   []uuid.UUID
+	EventTypes     []int
+	CommandTypes   []int
+	AggregateTypes []string
+}
+
+func (f Filter) Matches(aggregateID uuid.UUID, eventType, commandType int, aggregateType string) bool {
+	return (len(f.AggregateIDs) == 0 || slices.Contains(f.AggregateIDs, aggregateID)) &&
+		(len(f.EventTypes) == 0 || slices.Contains(f.EventTypes, eventType)) &&
+		(len(f.CommandTypes) == 0 || slices.Contains(f.CommandTypes, commandType)) &&
+		(len(f.AggregateTypes) == 0 || slices.Contains(f.AggregateTypes, aggregateType))
+}
+
+type StreamEvent[T any] struct {
+	Event[T]
+	Position      Position
+	AggregateType string
+}
@@ -0,0 +1,20 @@
+package outbox
+
+import (
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/google/uuid"
+)
+
+type Message[T any] struct {
+	ID          int64
+	AggregateID uuid.UUID
+	Event       events.IntegrationEvent[T]
+	Attempts    int
+}
+
+func NewMessage[T any](aggregateID uuid.UUID, event events.IntegrationEvent[T]) *Message[T] {
+	return &Message[T]{
+		AggregateID: aggregateID,
+		Event:       event,
+	}
+}
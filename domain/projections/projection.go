@@ -0,0 +1,58 @@
+package projections
+
+import (
+	"context"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+)
+
+const checkpointPrefix = "projection:"
+
+type HandlerFunc[T, E any] func(ctx context.Context, event events.Event[T], executor E) error
+
+type Projection[T, E any] struct {
+	name     string
+	handlers map[int]HandlerFunc[T, E]
+}
+
+func NewProjection[T, E any](name string) *Projection[T, E] {
+	return &Projection[T, E]{
+		name:     name,
+		handlers: make(map[int]HandlerFunc[T, E]),
+	}
+}
+
+func (p *Projection[T, E]) Name() string {
+	return p.name
+}
+
+func (p *Projection[T, E]) Checkpoint() string {
+	return checkpointPrefix + p.name
+}
+
+func (p *Projection[T, E]) On(eventType int, handler HandlerFunc[T, E]) *Projection[T, E] {
+	p.handlers[eventType] = handler
+	return p
+}
+
+func (p *Projection[T, E]) Handle(ctx context.Context, event events.Event[T], executor E) error {
+	handler, ok := p.handlers[event.Type]
+	if !ok {
+		return nil
+	}
+	return handler(ctx, event, executor)
+}
+
+type Lag struct {
+	Name     string
+	Position int64
+	Pending  int64
+}
+
+func NewLag(name string, position, pending int64) Lag {
+	return Lag{
+		Name:     name,
+		Position: position,
+		Pending:  pending,
+	}
+}
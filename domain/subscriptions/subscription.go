@@ -1,5 +1,15 @@
 package subscriptions
 
+const DefaultName = "default"
+
 type Subscription struct {
+	Name           string
 	LastSequenceID int64
 }
+
+func NewSubscription(name string, lastSequenceID int64) *Subscription {
+	return &Subscription{
+		Name:           name,
+		LastSequenceID: lastSequenceID,
+	}
+}
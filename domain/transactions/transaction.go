@@ -3,9 +3,11 @@ package transactions
 import "github.com/google/uuid"
 
 type Transaction struct {
-	ID          uuid.UUID
-	SequenceID  int64
-	AggregateID uuid.UUID
+	ID            uuid.UUID
+	SequenceID    int64
+	AggregateID   uuid.UUID
+	AggregateType string
+	CommandID     string
 }
 
 func NewTransaction(id, aggregateID uuid.UUID, sequenceID int64) *Transaction {
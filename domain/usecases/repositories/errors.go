@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrConcurrencyConflict = errors.New("concurrency conflict")
+	ErrDuplicateCommand    = errors.New("duplicate command")
+	ErrProjectionNotFound  = errors.New("projection not found")
+	ErrSubscriptionLocked  = errors.New("subscription is locked")
+)
+
+type ConcurrencyConflictError struct {
+	AggregateID     uuid.UUID
+	ExpectedVersion int
+	ActualVersion   int
+}
+
+func NewConcurrencyConflictError(
+	aggregateID uuid.UUID,
+	expectedVersion, actualVersion int,
+) *ConcurrencyConflictError {
+	return &ConcurrencyConflictError{
+		AggregateID:     aggregateID,
+		ExpectedVersion: expectedVersion,
+		ActualVersion:   actualVersion,
+	}
+}
+
+func (e *ConcurrencyConflictError) Error() string {
+	return fmt.Sprintf(
+		"%s: aggregate %s expected version %d, actual version %d",
+		ErrConcurrencyConflict,
+		e.AggregateID,
+		e.ExpectedVersion,
+		e.ActualVersion,
+	)
+}
+
+func (e *ConcurrencyConflictError) Is(target error) bool {
+	return target == ErrConcurrencyConflict //nolint:errorlint // sentinel comparison
+}
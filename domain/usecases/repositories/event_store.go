@@ -2,10 +2,12 @@ package repositories
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
-	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
 	"github.com/google/uuid"
 )
 
@@ -17,9 +19,10 @@ type TFACommitter[E any] interface {
 
 type EventStore[T, S, E any] interface {
 	TFACommitter[E]
+	SubscriptionStore[E]
 	UpdateOrCreateAggregate(
 		ctx context.Context,
-		transactionID uuid.UUID,
+		transaction *transactions.Transaction,
 		reader entities.AggregateReader[T],
 		snapshot S,
 		executor E,
@@ -30,6 +33,12 @@ type EventStore[T, S, E any] interface {
 		versionAfter *int,
 		executor E,
 	) (int, S, error)
+	GetSnapshotAsOf(
+		ctx context.Context,
+		id uuid.UUID,
+		asOf time.Time,
+		executor E,
+	) (int, S, error)
 	GetEvents(
 		ctx context.Context,
 		id uuid.UUID,
@@ -37,16 +46,58 @@ type EventStore[T, S, E any] interface {
 		toVersion *int,
 		executor E,
 	) ([]events.Event[T], error)
+	GetEventsUntil(
+		ctx context.Context,
+		id uuid.UUID,
+		fromVersion int,
+		until time.Time,
+		executor E,
+	) ([]events.Event[T], error)
 	GetUnhandledEvents(
 		ctx context.Context,
 		id uuid.UUID,
 		firstSequenceID, lastSequenceID int64,
 		executor E,
 	) ([]events.Event[T], error)
-	GetSubscription(ctx context.Context, executor E) (*subscriptions.Subscription, error)
-	UpdateSubscription(
+	ReadAll(
 		ctx context.Context,
-		sub *subscriptions.Subscription,
+		fromPosition events.Position,
+		limit int,
+		filter events.Filter,
 		executor E,
-	) error
+	) iter.Seq2[events.StreamEvent[T], error]
+	GetTransactions(
+		ctx context.Context,
+		afterSequenceID int64,
+		limit int,
+		executor E,
+	) ([]*transactions.Transaction, error)
+	GetAggregateIDs(
+		ctx context.Context,
+		afterID uuid.UUID,
+		limit int,
+		aggregateType string,
+		executor E,
+	) ([]uuid.UUID, error)
+	GetAggregateTypes(
+		ctx context.Context,
+		ids []uuid.UUID,
+		executor E,
+	) (map[uuid.UUID]string, error)
+	CountTransactions(
+		ctx context.Context,
+		afterSequenceID int64,
+		executor E,
+	) (int64, error)
+	HasCommand(
+		ctx context.Context,
+		commandID string,
+		since time.Time,
+		executor E,
+	) (bool, error)
+	PurgeCommands(
+		ctx context.Context,
+		before time.Time,
+		executor E,
+	) (int64, error)
 }
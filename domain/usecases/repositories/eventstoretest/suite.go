@@ -0,0 +1,648 @@
+package eventstoretest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	snapshotCap = 2
+	writePause  = 10 * time.Millisecond
+	commandType = 1
+	eventType   = 2
+)
+
+type Payload struct {
+	Value int `json:"value"`
+}
+
+type Snapshot struct {
+	Version int `json:"version"`
+}
+
+type Factory[E any] func(t *testing.T) repositories.EventStore[Payload, Snapshot, E]
+
+type suite[E any] struct {
+	t     *testing.T
+	ctx   context.Context
+	store repositories.EventStore[Payload, Snapshot, E]
+	types map[uuid.UUID]string
+}
+
+func Run[E any](t *testing.T, factory Factory[E]) {
+	t.Helper()
+	newSuite := func(t *testing.T) *suite[E] {
+		t.Helper()
+		return &suite[E]{
+			t:     t,
+			ctx:   context.Background(),
+			store: factory(t),
+			types: make(map[uuid.UUID]string),
+		}
+	}
+	t.Run("Новый агрегат должен сохранять события и снимки на границах емкости", func(t *testing.T) {
+		newSuite(t).testSnapshots()
+	})
+	t.Run("Запись с устаревшей версией должна возвращать ошибку конфликта версий", func(t *testing.T) {
+		newSuite(t).testVersionConflicts()
+	})
+	t.Run("Метод GetEvents должен возвращать события в пределах указанных версий", func(t *testing.T) {
+		newSuite(t).testEventRanges()
+	})
+	t.Run("Запросы на момент времени должны учитывать только снимки и события до этого момента", func(t *testing.T) {
+		newSuite(t).testTemporalQueries()
+	})
+	t.Run("Метод GetUnhandledEvents должен возвращать события транзакций агрегата по порядку", func(t *testing.T) {
+		newSuite(t).testUnhandledEvents()
+	})
+	t.Run("Метод ReadAll должен возвращать события всех агрегатов в порядке фиксации", func(t *testing.T) {
+		newSuite(t).testReadAll()
+	})
+	t.Run("Подписки должны создаваться, обновляться и сбрасываться", func(t *testing.T) {
+		newSuite(t).testSubscriptions()
+	})
+	t.Run("Изменения откаченной транзакции не должны быть видны", func(t *testing.T) {
+		newSuite(t).testRollbackVisibility()
+	})
+	t.Run("Повторный идентификатор команды должен отклоняться до истечения срока", func(t *testing.T) {
+		newSuite(t).testCommands()
+	})
+}
+
+func (s *suite[E]) testSnapshots() {
+	id := uuid.New()
+	s.mustWrite(id, 0, "", 1, 2)
+	s.mustWrite(id, 2, "", 3)
+	s.mustWrite(id, 3, "", 4, 5)
+
+	s.read(func(tx E) {
+		snapshotCases := []struct {
+			versionAfter    *int
+			expectedVersion int
+		}{
+			{versionAfter: nil, expectedVersion: 5},
+			{versionAfter: ptr(6), expectedVersion: 5},
+			{versionAfter: ptr(5), expectedVersion: 2},
+			{versionAfter: ptr(3), expectedVersion: 2},
+			{versionAfter: ptr(2), expectedVersion: 0},
+		}
+		for _, sc := range snapshotCases {
+			version, payload, err := s.store.GetSnapshot(s.ctx, id, sc.versionAfter, tx)
+			require.NoError(s.t, err)
+			assert.Equal(s.t, sc.expectedVersion, version)
+			assert.Equal(s.t, Snapshot{Version: sc.expectedVersion}, payload)
+		}
+
+		version, payload, err := s.store.GetSnapshot(s.ctx, uuid.New(), nil, tx)
+		require.NoError(s.t, err)
+		assert.Zero(s.t, version)
+		assert.Equal(s.t, Snapshot{}, payload)
+	})
+}
+
+func (s *suite[E]) testVersionConflicts() {
+	id := uuid.New()
+	s.mustWrite(id, 0, "", 1, 2)
+
+	conflictCases := []struct {
+		id              uuid.UUID
+		baseVersion     int
+		expectedVersion int
+		actualVersion   int
+	}{
+		{id: id, baseVersion: 0, expectedVersion: 0, actualVersion: 2},
+		{id: id, baseVersion: 1, expectedVersion: 1, actualVersion: 2},
+		{id: id, baseVersion: 3, expectedVersion: 3, actualVersion: 2},
+		{id: uuid.New(), baseVersion: 1, expectedVersion: 1, actualVersion: 0},
+	}
+	for _, cc := range conflictCases {
+		err := s.write(cc.id, cc.baseVersion, "", 10)
+		require.ErrorIs(s.t, err, repositories.ErrConcurrencyConflict)
+		var conflict *repositories.ConcurrencyConflictError
+		require.ErrorAs(s.t, err, &conflict)
+		assert.Equal(s.t, cc.id, conflict.AggregateID)
+		assert.Equal(s.t, cc.expectedVersion, conflict.ExpectedVersion)
+		assert.Equal(s.t, cc.actualVersion, conflict.ActualVersion)
+	}
+
+	s.mustWrite(id, 2, "", 3)
+	s.read(func(tx E) {
+		history, err := s.store.GetEvents(s.ctx, id, 0, nil, tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, []int{1, 2, 3}, values(history))
+	})
+}
+
+func (s *suite[E]) testEventRanges() {
+	id := uuid.New()
+	first := s.mustWrite(id, 0, "", 1, 2, 3)
+	second := s.mustWrite(id, 3, "", 4, 5)
+
+	s.read(func(tx E) {
+		rangeCases := []struct {
+			fromVersion int
+			toVersion   *int
+			expected    []int
+		}{
+			{fromVersion: 0, toVersion: nil, expected: []int{1, 2, 3, 4, 5}},
+			{fromVersion: 3, toVersion: nil, expected: []int{3, 4, 5}},
+			{fromVersion: 2, toVersion: ptr(4), expected: []int{2, 3, 4}},
+			{fromVersion: 5, toVersion: ptr(5), expected: []int{5}},
+			{fromVersion: 6, toVersion: nil, expected: []int{}},
+			{fromVersion: 4, toVersion: ptr(3), expected: []int{}},
+		}
+		for _, rc := range rangeCases {
+			history, err := s.store.GetEvents(s.ctx, id, rc.fromVersion, rc.toVersion, tx)
+			require.NoError(s.t, err)
+			assert.Equal(s.t, rc.expected, values(history))
+		}
+
+		history, err := s.store.GetEvents(s.ctx, id, 0, nil, tx)
+		require.NoError(s.t, err)
+		require.Len(s.t, history, 5)
+		for i, event := range history {
+			assert.Equal(s.t, id, event.AggregateID)
+			assert.Equal(s.t, i+1, event.Version)
+			assert.Equal(s.t, commandType, event.CommandType)
+			assert.Equal(s.t, eventType, event.Type)
+			assert.Equal(s.t, metadata(i+1), event.Metadata)
+			assert.NotNil(s.t, event.CreatedAt)
+		}
+		assert.Equal(s.t, first.ID, history[0].TransactionID)
+		assert.Equal(s.t, second.ID, history[4].TransactionID)
+
+		empty, err := s.store.GetEvents(s.ctx, uuid.New(), 0, nil, tx)
+		require.NoError(s.t, err)
+		assert.Empty(s.t, empty)
+	})
+}
+
+func (s *suite[E]) testTemporalQueries() {
+	id := uuid.New()
+	s.mustWrite(id, 0, "", 1, 2)
+	time.Sleep(writePause)
+	s.mustWrite(id, 2, "", 3)
+	time.Sleep(writePause)
+	s.mustWrite(id, 3, "", 4, 5)
+
+	s.read(func(tx E) {
+		history, err := s.store.GetEvents(s.ctx, id, 0, nil, tx)
+		require.NoError(s.t, err)
+		require.Len(s.t, history, 5)
+		createdAt := func(version int) time.Time {
+			return *history[version-1].CreatedAt
+		}
+		temporalCases := []struct {
+			asOf             time.Time
+			expectedSnapshot int
+			expected         []int
+		}{
+			{asOf: createdAt(1).Add(-time.Millisecond), expectedSnapshot: 0, expected: []int{}},
+			{asOf: createdAt(2), expectedSnapshot: 2, expected: []int{}},
+			{asOf: createdAt(3), expectedSnapshot: 2, expected: []int{3}},
+			{asOf: createdAt(3).Add(writePause / 2), expectedSnapshot: 2, expected: []int{3}},
+			{asOf: createdAt(5), expectedSnapshot: 5, expected: []int{}},
+		}
+		for _, tc := range temporalCases {
+			version, payload, errSnapshot := s.store.GetSnapshotAsOf(s.ctx, id, tc.asOf, tx)
+			require.NoError(s.t, errSnapshot)
+			assert.Equal(s.t, tc.expectedSnapshot, version)
+			assert.Equal(s.t, Snapshot{Version: tc.expectedSnapshot}, payload)
+
+			until, errUntil := s.store.GetEventsUntil(s.ctx, id, version+1, tc.asOf, tx)
+			require.NoError(s.t, errUntil)
+			assert.Equal(s.t, tc.expected, values(until))
+		}
+
+		all, err := s.store.GetEventsUntil(s.ctx, id, 0, createdAt(3), tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, []int{1, 2, 3}, values(all))
+	})
+}
+
+func (s *suite[E]) testUnhandledEvents() {
+	first, second := uuid.New(), uuid.New()
+	s.types[first], s.types[second] = "first", "second"
+	s.mustWrite(first, 0, "", 1, 2)
+	s.mustWrite(second, 0, "", 10)
+	s.mustWrite(first, 2, "", 3)
+	s.mustWrite(second, 1, "", 20)
+	s.mustWrite(first, 3, "", 4, 5)
+
+	s.read(func(tx E) {
+		batch, err := s.store.GetTransactions(s.ctx, 0, 100, tx)
+		require.NoError(s.t, err)
+		require.Len(s.t, batch, 5)
+		for i := 1; i < len(batch); i++ {
+			assert.Greater(s.t, batch[i].SequenceID, batch[i-1].SequenceID)
+		}
+		aggregateIDs := make([]uuid.UUID, 0, len(batch))
+		aggregateTypes := make([]string, 0, len(batch))
+		for _, transaction := range batch {
+			aggregateIDs = append(aggregateIDs, transaction.AggregateID)
+			aggregateTypes = append(aggregateTypes, transaction.AggregateType)
+		}
+		assert.Equal(s.t, []uuid.UUID{first, second, first, second, first}, aggregateIDs)
+		assert.Equal(s.t, []string{"first", "second", "first", "second", "first"}, aggregateTypes)
+
+		page, err := s.store.GetTransactions(s.ctx, batch[1].SequenceID, 2, tx)
+		require.NoError(s.t, err)
+		require.Len(s.t, page, 2)
+		assert.Equal(s.t, batch[2].ID, page[0].ID)
+		assert.Equal(s.t, batch[3].ID, page[1].ID)
+
+		count, err := s.store.CountTransactions(s.ctx, batch[1].SequenceID, tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, int64(3), count)
+
+		unhandledCases := []struct {
+			id              uuid.UUID
+			firstSequenceID int64
+			lastSequenceID  int64
+			expected        []int
+		}{
+			{id: first, firstSequenceID: 0, lastSequenceID: batch[4].SequenceID, expected: []int{1, 2, 3, 4, 5}},
+			{id: first, firstSequenceID: batch[0].SequenceID, lastSequenceID: batch[4].SequenceID, expected: []int{3, 4, 5}},
+			{id: first, firstSequenceID: 0, lastSequenceID: batch[2].SequenceID, expected: []int{1, 2, 3}},
+			{id: second, firstSequenceID: batch[1].SequenceID, lastSequenceID: batch[4].SequenceID, expected: []int{20}},
+			{id: second, firstSequenceID: batch[3].SequenceID, lastSequenceID: batch[4].SequenceID, expected: []int{}},
+		}
+		for _, uc := range unhandledCases {
+			unhandled, errUnhandled := s.store.GetUnhandledEvents(
+				s.ctx,
+				uc.id,
+				uc.firstSequenceID,
+				uc.lastSequenceID,
+				tx,
+			)
+			require.NoError(s.t, errUnhandled)
+			assert.Equal(s.t, uc.expected, values(unhandled))
+		}
+
+		ids, err := s.store.GetAggregateIDs(s.ctx, uuid.Nil, 10, "", tx)
+		require.NoError(s.t, err)
+		expectedIDs := []uuid.UUID{first, second}
+		if first.String() > second.String() {
+			expectedIDs = []uuid.UUID{second, first}
+		}
+		assert.Equal(s.t, expectedIDs, ids)
+
+		ids, err = s.store.GetAggregateIDs(s.ctx, expectedIDs[0], 10, "", tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, expectedIDs[1:], ids)
+
+		ids, err = s.store.GetAggregateIDs(s.ctx, uuid.Nil, 10, "second", tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, []uuid.UUID{second}, ids)
+
+		ids, err = s.store.GetAggregateIDs(s.ctx, uuid.Nil, 10, "unknown", tx)
+		require.NoError(s.t, err)
+		assert.Empty(s.t, ids)
+
+		types, errTypes := s.store.GetAggregateTypes(s.ctx, []uuid.UUID{first, second, uuid.New()}, tx)
+		require.NoError(s.t, errTypes)
+		assert.Equal(s.t, map[uuid.UUID]string{first: "first", second: "second"}, types)
+	})
+}
+
+func (s *suite[E]) testReadAll() {
+	first, second, bulk := uuid.New(), uuid.New(), uuid.New()
+	s.types[first], s.types[second], s.types[bulk] = "first", "second", "bulk"
+	s.mustWrite(first, 0, "", 1, 2)
+	s.mustWrite(second, 0, "", 10)
+	s.mustWrite(first, 2, "", 3)
+	s.mustWrite(second, 1, "", 20)
+	bulkValues := make([]int, repositories.ReadAllPageSize+5)
+	for i := range bulkValues {
+		bulkValues[i] = 1000 + i
+	}
+	s.mustWrite(bulk, 0, "", bulkValues...)
+
+	s.read(func(tx E) {
+		all := s.readAll(events.Position{}, 0, events.Filter{}, tx)
+		require.Len(s.t, all, 5+len(bulkValues))
+		assert.Equal(s.t, []int{1, 2, 10, 3, 20}, streamValues(all[:5]))
+		assert.Equal(s.t, bulkValues, streamValues(all[5:]))
+		for i := 1; i < len(all); i++ {
+			assert.True(s.t, all[i].Position.After(all[i-1].Position))
+		}
+		assert.Equal(s.t, "first", all[0].AggregateType)
+		assert.Equal(s.t, first, all[0].AggregateID)
+		assert.Equal(s.t, all[0].Version, all[0].Position.Version)
+		assert.Equal(s.t, metadata(1), all[0].Metadata)
+		assert.NotNil(s.t, all[0].CreatedAt)
+
+		readCases := []struct {
+			from     events.Position
+			limit    int
+			filter   events.Filter
+			expected []int
+		}{
+			{from: events.Position{}, limit: 3, expected: []int{1, 2, 10}},
+			{from: all[0].Position, limit: 2, expected: []int{2, 10}},
+			{from: all[1].Position, limit: 3, expected: []int{10, 3, 20}},
+			{from: all[len(all)-1].Position, expected: []int{}},
+			{filter: events.Filter{AggregateTypes: []string{"second"}}, expected: []int{10, 20}},
+			{from: all[2].Position, filter: events.Filter{AggregateTypes: []string{"first", "second"}}, expected: []int{3, 20}},
+			{limit: 2, filter: events.Filter{EventTypes: []int{eventType}}, expected: []int{1, 2}},
+			{filter: events.Filter{AggregateIDs: []uuid.UUID{first}}, expected: []int{1, 2, 3}},
+			{
+				from:     all[0].Position,
+				filter:   events.Filter{AggregateIDs: []uuid.UUID{first, second}},
+				expected: []int{2, 10, 3, 20},
+			},
+			{filter: events.Filter{EventTypes: []int{eventType + 1}}, expected: []int{}},
+			{filter: events.Filter{CommandTypes: []int{commandType + 1}}, expected: []int{}},
+			{
+				limit:    2,
+				filter:   events.Filter{CommandTypes: []int{commandType}, AggregateTypes: []string{"second"}},
+				expected: []int{10, 20},
+			},
+		}
+		for _, rc := range readCases {
+			assert.Equal(s.t, rc.expected, streamValues(s.readAll(rc.from, rc.limit, rc.filter, tx)))
+		}
+
+		read := 0
+		for _, err := range s.store.ReadAll(s.ctx, events.Position{}, 0, events.Filter{}, tx) {
+			require.NoError(s.t, err)
+			read++
+			if read == 2 {
+				break
+			}
+		}
+		assert.Equal(s.t, 2, read)
+
+		seq := s.store.ReadAll(s.ctx, all[0].Position, 0, events.Filter{AggregateIDs: []uuid.UUID{first}}, tx)
+		for range 2 {
+			ranged := make([]events.StreamEvent[Payload], 0)
+			for event, err := range seq {
+				require.NoError(s.t, err)
+				ranged = append(ranged, event)
+			}
+			assert.Equal(s.t, []int{2, 3}, streamValues(ranged))
+		}
+	})
+}
+
+func (s *suite[E]) testSubscriptions() {
+	s.commit(func(tx E) error {
+		sub, err := s.store.GetSubscription(s.ctx, "projector", tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, subscriptions.NewSubscription("projector", 0), sub)
+		return s.store.UpdateSubscription(s.ctx, subscriptions.NewSubscription("projector", 5), tx)
+	})
+	s.commit(func(tx E) error {
+		return s.store.ResetSubscription(s.ctx, "audit", 3, tx)
+	})
+
+	s.read(func(tx E) {
+		sub, err := s.store.GetSubscription(s.ctx, "projector", tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, int64(5), sub.LastSequenceID)
+
+		subs, err := s.store.GetSubscriptions(s.ctx, tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, []*subscriptions.Subscription{
+			subscriptions.NewSubscription("audit", 3),
+			subscriptions.NewSubscription("projector", 5),
+		}, subs)
+	})
+
+	s.commit(func(tx E) error {
+		return s.store.ResetSubscription(s.ctx, "projector", 1, tx)
+	})
+	s.read(func(tx E) {
+		sub, err := s.store.GetSubscription(s.ctx, "projector", tx)
+		require.NoError(s.t, err)
+		assert.Equal(s.t, int64(1), sub.LastSequenceID)
+	})
+}
+
+func (s *suite[E]) testRollbackVisibility() {
+	id := uuid.New()
+	s.mustWrite(id, 0, "", 1)
+	s.commit(func(tx E) error {
+		return s.store.ResetSubscription(s.ctx, "projector", 1, tx)
+	})
+
+	tx, err := s.store.Begin(s.ctx)
+	require.NoError(s.t, err)
+	require.NoError(s.t, s.store.UpdateOrCreateAggregate(
+		s.ctx,
+		transactions.NewTransaction(uuid.New(), id, 0),
+		s.aggregate(id, 1, 2, 3),
+		Snapshot{Version: 3},
+		tx,
+	))
+	other := uuid.New()
+	require.NoError(s.t, s.store.UpdateOrCreateAggregate(
+		s.ctx,
+		transactions.NewTransaction(uuid.New(), other, 0),
+		s.aggregate(other, 0, 7),
+		Snapshot{Version: 1},
+		tx,
+	))
+	require.NoError(s.t, s.store.UpdateSubscription(s.ctx, subscriptions.NewSubscription("projector", 100), tx))
+	require.NoError(s.t, s.store.Rollback(s.ctx, tx))
+
+	s.read(func(tx E) {
+		history, errEvents := s.store.GetEvents(s.ctx, id, 0, nil, tx)
+		require.NoError(s.t, errEvents)
+		assert.Equal(s.t, []int{1}, values(history))
+
+		version, _, errSnapshot := s.store.GetSnapshot(s.ctx, id, nil, tx)
+		require.NoError(s.t, errSnapshot)
+		assert.Zero(s.t, version)
+
+		batch, errTransactions := s.store.GetTransactions(s.ctx, 0, 100, tx)
+		require.NoError(s.t, errTransactions)
+		assert.Len(s.t, batch, 1)
+
+		sub, errSub := s.store.GetSubscription(s.ctx, "projector", tx)
+		require.NoError(s.t, errSub)
+		assert.Equal(s.t, int64(1), sub.LastSequenceID)
+	})
+
+	s.mustWrite(other, 0, "", 7)
+	s.mustWrite(id, 1, "", 2)
+}
+
+func (s *suite[E]) testCommands() {
+	id := uuid.New()
+	s.mustWrite(id, 0, "command", 1)
+
+	err := s.write(id, 1, "command", 2)
+	require.ErrorIs(s.t, err, repositories.ErrDuplicateCommand)
+
+	s.read(func(tx E) {
+		exists, errHas := s.store.HasCommand(s.ctx, "command", time.Now().Add(-time.Hour), tx)
+		require.NoError(s.t, errHas)
+		assert.True(s.t, exists)
+
+		exists, errHas = s.store.HasCommand(s.ctx, "unknown", time.Now().Add(-time.Hour), tx)
+		require.NoError(s.t, errHas)
+		assert.False(s.t, exists)
+	})
+
+	s.read(func(tx E) {
+		exists, errHas := s.store.HasCommand(s.ctx, "command", time.Now().Add(time.Hour), tx)
+		require.NoError(s.t, errHas)
+		assert.False(s.t, exists)
+	})
+	err = s.write(id, 1, "command", 2)
+	require.ErrorIs(s.t, err, repositories.ErrDuplicateCommand)
+
+	s.commit(func(tx E) error {
+		purged, errPurge := s.store.PurgeCommands(s.ctx, time.Now().Add(-time.Hour), tx)
+		require.NoError(s.t, errPurge)
+		assert.Zero(s.t, purged)
+		return nil
+	})
+	s.commit(func(tx E) error {
+		purged, errPurge := s.store.PurgeCommands(s.ctx, time.Now().Add(time.Hour), tx)
+		require.NoError(s.t, errPurge)
+		assert.Equal(s.t, int64(1), purged)
+		return nil
+	})
+
+	s.mustWrite(id, 1, "command", 2)
+}
+
+func (s *suite[E]) aggregate(id uuid.UUID, baseVersion int, eventValues ...int) entities.AggregateReader[Payload] {
+	aggregate := entities.NewAggregate[Payload, Snapshot](
+		id,
+		snapshotCap,
+		func(events.Event[Payload]) error { return nil },
+		func(Snapshot) error { return nil },
+	).WithType(s.types[id])
+	if baseVersion > 0 {
+		require.NoError(s.t, aggregate.BuildFromSnapshot(baseVersion, Snapshot{}))
+	}
+	transactionID := uuid.New()
+	for i, value := range eventValues {
+		version := baseVersion + i + 1
+		event := events.NewEvent(id, transactionID, commandType, version, eventType, Payload{Value: value}).
+			WithMetadata(metadata(version))
+		require.NoError(s.t, aggregate.ApplyChange(event))
+	}
+	return aggregate
+}
+
+func (s *suite[E]) write(
+	id uuid.UUID,
+	baseVersion int,
+	commandID string,
+	eventValues ...int,
+) error {
+	_, err := s.writeTransaction(id, baseVersion, commandID, eventValues...)
+	return err
+}
+
+func (s *suite[E]) writeTransaction(
+	id uuid.UUID,
+	baseVersion int,
+	commandID string,
+	eventValues ...int,
+) (*transactions.Transaction, error) {
+	aggregate := s.aggregate(id, baseVersion, eventValues...)
+	transaction := transactions.NewTransaction(aggregate.Changes()[0].TransactionID, id, 0)
+	transaction.CommandID = commandID
+
+	tx, err := s.store.Begin(s.ctx)
+	require.NoError(s.t, err)
+	err = s.store.UpdateOrCreateAggregate(
+		s.ctx,
+		transaction,
+		aggregate,
+		Snapshot{Version: aggregate.Version()},
+		tx,
+	)
+	if err != nil {
+		require.NoError(s.t, s.store.Rollback(s.ctx, tx))
+		return nil, err
+	}
+	require.NoError(s.t, s.store.Commit(s.ctx, tx))
+	return transaction, nil
+}
+
+func (s *suite[E]) mustWrite(
+	id uuid.UUID,
+	baseVersion int,
+	commandID string,
+	eventValues ...int,
+) *transactions.Transaction {
+	transaction, err := s.writeTransaction(id, baseVersion, commandID, eventValues...)
+	require.NoError(s.t, err)
+	return transaction
+}
+
+func (s *suite[E]) read(fn func(tx E)) {
+	tx, err := s.store.Begin(s.ctx)
+	require.NoError(s.t, err)
+	defer func() {
+		require.NoError(s.t, s.store.Rollback(s.ctx, tx))
+	}()
+	fn(tx)
+}
+
+func (s *suite[E]) commit(fn func(tx E) error) {
+	tx, err := s.store.Begin(s.ctx)
+	require.NoError(s.t, err)
+	if err = fn(tx); err != nil {
+		require.NoError(s.t, s.store.Rollback(s.ctx, tx))
+		require.NoError(s.t, err)
+	}
+	require.NoError(s.t, s.store.Commit(s.ctx, tx))
+}
+
+func (s *suite[E]) readAll(
+	from events.Position,
+	limit int,
+	filter events.Filter,
+	tx E,
+) []events.StreamEvent[Payload] {
+	result := make([]events.StreamEvent[Payload], 0)
+	for event, err := range s.store.ReadAll(s.ctx, from, limit, filter, tx) {
+		require.NoError(s.t, err)
+		result = append(result, event)
+	}
+	return result
+}
+
+func streamValues(stream []events.StreamEvent[Payload]) []int {
+	result := make([]int, 0, len(stream))
+	for _, event := range stream {
+		result = append(result, event.Payload.Value)
+	}
+	return result
+}
+
+func values(history []events.Event[Payload]) []int {
+	result := make([]int, 0, len(history))
+	for _, event := range history {
+		result = append(result, event.Payload.Value)
+	}
+	return result
+}
+
+func metadata(version int) events.Metadata {
+	return events.NewMetadata("correlation", "causation", "actor", map[string]string{
+		"version": strconv.Itoa(version),
+	})
+}
+
+func ptr(value int) *int {
+	return &value
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
+)
+
+type OutboxStore[K, E any] interface {
+	Enqueue(ctx context.Context, messages []*outbox.Message[K], executor E) error
+	GetPending(ctx context.Context, limit int, executor E) ([]*outbox.Message[K], error)
+	MarkSent(ctx context.Context, ids []int64, executor E) error
+	MarkFailed(ctx context.Context, ids []int64, reason string, executor E) error
+	Park(ctx context.Context, ids []int64, reason string, executor E) error
+}
@@ -5,3 +5,7 @@ import "context"
 type ProjectionStore[T any] interface {
 	Save(ctx context.Context, projection T) error
 }
+
+type TransactionalProjectionStore[T, E any] interface {
+	Save(ctx context.Context, projection T, executor E) error
+}
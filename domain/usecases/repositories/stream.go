@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"iter"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+)
+
+const ReadAllPageSize = 100
+
+func ReadPages[T any](
+	from events.Position,
+	limit int,
+	fetch func(after events.Position, limit int) ([]events.StreamEvent[T], error),
+) iter.Seq2[events.StreamEvent[T], error] {
+	return func(yield func(events.StreamEvent[T], error) bool) {
+		after := from
+		remaining := limit
+		for limit <= 0 || remaining > 0 {
+			size := ReadAllPageSize
+			if limit > 0 {
+				size = min(size, remaining)
+			}
+			page, err := fetch(after, size)
+			if err != nil {
+				yield(events.StreamEvent[T]{}, err)
+				return
+			}
+			for _, event := range page {
+				if !yield(event, nil) {
+					return
+				}
+				after = event.Position
+			}
+			if len(page) < size {
+				return
+			}
+			remaining -= len(page)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+)
+
+type SubscriptionStore[E any] interface {
+	GetSubscription(ctx context.Context, name string, executor E) (*subscriptions.Subscription, error)
+	UpdateSubscription(
+		ctx context.Context,
+		sub *subscriptions.Subscription,
+		executor E,
+	) error
+	GetSubscriptions(ctx context.Context, executor E) ([]*subscriptions.Subscription, error)
+	ResetSubscription(
+		ctx context.Context,
+		name string,
+		lastSequenceID int64,
+		executor E,
+	) error
+}
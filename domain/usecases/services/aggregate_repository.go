@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+)
+
+const firstVersion = 1
+
+var ErrAggregateVersionNotFound = errors.New("aggregate version not found")
+
+type AggregateRepository[T, S, P, K any] interface {
+	Load(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K]) error
+	LoadAtVersion(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], version int) error
+	LoadAsOf(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], asOf time.Time) error
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type aggregateRepository[T, S, P, K, E any] struct {
+	store repositories.EventStore[T, S, E]
+}
+
+func NewAggregateRepository[T, S, P, K, E any](
+	store repositories.EventStore[T, S, E],
+) AggregateRepository[T, S, P, K] {
+	return newAggregateRepository[T, S, P, K](store)
+}
+
+func newAggregateRepository[T, S, P, K, E any](
+	store repositories.EventStore[T, S, E],
+) *aggregateRepository[T, S, P, K, E] {
+	return &aggregateRepository[T, S, P, K, E]{store: store}
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) Load(
+	ctx context.Context,
+	aggregate entities.AggregateProvider[T, S, P, K],
+) error {
+	return r.read(ctx, func(executor E) error {
+		return r.load(ctx, aggregate, nil, executor)
+	})
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) LoadAtVersion(
+	ctx context.Context,
+	aggregate entities.AggregateProvider[T, S, P, K],
+	version int,
+) error {
+	return r.read(ctx, func(executor E) error {
+		if err := r.load(ctx, aggregate, &version, executor); err != nil {
+			return err
+		}
+		if aggregate.Version() != version {
+			return fmt.Errorf(
+				"%w: aggregate %s has no version %d",
+				ErrAggregateVersionNotFound,
+				aggregate.ID(),
+				version,
+			)
+		}
+		return nil
+	})
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) LoadAsOf(
+	ctx context.Context,
+	aggregate entities.AggregateProvider[T, S, P, K],
+	asOf time.Time,
+) error {
+	return r.read(ctx, func(executor E) error {
+		version, payload, err := r.store.GetSnapshotAsOf(ctx, aggregate.ID(), asOf, executor)
+		if err != nil {
+			return err
+		}
+		currentVersion, err := buildFromSnapshot(aggregate, version, payload)
+		if err != nil {
+			return err
+		}
+		history, err := r.store.GetEventsUntil(ctx, aggregate.ID(), currentVersion+1, asOf, executor)
+		if err != nil {
+			return err
+		}
+		return aggregate.Build(history)
+	})
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(executor E) error {
+		toVersion := firstVersion
+		history, err := r.store.GetEvents(ctx, id, firstVersion, &toVersion, executor)
+		exists = len(history) > 0
+		return err
+	})
+	return exists, err
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) read(ctx context.Context, fn func(executor E) error) error {
+	executor, err := r.store.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(executor)
+	if rollbackErr := r.store.Rollback(ctx, executor); err == nil {
+		err = rollbackErr
+	}
+	return err
+}
+
+func (r *aggregateRepository[T, S, P, K, E]) load(
+	ctx context.Context,
+	aggregate entities.AggregateProvider[T, S, P, K],
+	toVersion *int,
+	executor E,
+) error {
+	var versionAfter *int
+	if toVersion != nil {
+		next := *toVersion + 1
+		versionAfter = &next
+	}
+	version, payload, err := r.store.GetSnapshot(ctx, aggregate.ID(), versionAfter, executor)
+	if err != nil {
+		return err
+	}
+	currentVersion, err := buildFromSnapshot(aggregate, version, payload)
+	if err != nil {
+		return err
+	}
+	if toVersion != nil && *toVersion <= currentVersion {
+		return nil
+	}
+	history, err := r.store.GetEvents(ctx, aggregate.ID(), currentVersion+1, toVersion, executor)
+	if err != nil {
+		return err
+	}
+	return aggregate.Build(history)
+}
+
+func buildFromSnapshot[T, S, P, K any](
+	aggregate entities.AggregateProvider[T, S, P, K],
+	version int,
+	payload S,
+) (int, error) {
+	if version == 0 {
+		return -1, nil
+	}
+	return version, aggregate.BuildFromSnapshot(version, payload)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+)
+
+type transactionFunc[E any] func(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	lastSequenceID int64,
+	commitExecutor E,
+) error
+
+type catchUpLoop[T, S, E any] struct {
+	eventStore repositories.EventStore[T, S, E]
+	checkpoint string
+	batchSize  int
+	log        *slog.Logger
+}
+
+func (l catchUpLoop[T, S, E]) run(ctx context.Context, handle transactionFunc[E]) error {
+	for {
+		handled, err := l.handleBatch(ctx, handle)
+		if err != nil {
+			l.log.ErrorContext(ctx, err.Error())
+			return err
+		}
+		if handled < l.batchSize {
+			return nil
+		}
+	}
+}
+
+func (l catchUpLoop[T, S, E]) handleBatch(ctx context.Context, handle transactionFunc[E]) (int, error) {
+	commitExecutor, err := l.eventStore.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	handled, err := l.processBatch(ctx, handle, commitExecutor)
+	if err != nil {
+		if rollbackErr := l.eventStore.Rollback(ctx, commitExecutor); rollbackErr != nil {
+			err = errors.Join(err, rollbackErr)
+		}
+		return 0, err
+	}
+	return handled, l.eventStore.Commit(ctx, commitExecutor)
+}
+
+func (l catchUpLoop[T, S, E]) processBatch(
+	ctx context.Context,
+	handle transactionFunc[E],
+	commitExecutor E,
+) (int, error) {
+	sub, err := l.eventStore.GetSubscription(ctx, l.checkpoint, commitExecutor)
+	if errors.Is(err, repositories.ErrSubscriptionLocked) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	batch, err := l.eventStore.GetTransactions(ctx, sub.LastSequenceID, l.batchSize, commitExecutor)
+	if err != nil {
+		return 0, err
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	lastSequenceID := sub.LastSequenceID
+	for _, transaction := range batch {
+		if err = handle(ctx, transaction, lastSequenceID, commitExecutor); err != nil {
+			return 0, err
+		}
+		lastSequenceID = transaction.SequenceID
+	}
+	err = l.eventStore.UpdateSubscription(
+		ctx,
+		subscriptions.NewSubscription(l.checkpoint, lastSequenceID),
+		commitExecutor,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
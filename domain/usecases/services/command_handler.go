@@ -2,38 +2,137 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/commands"
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 	"github.com/google/uuid"
 )
 
+const DefaultIdempotencyRetention = 24 * time.Hour
+
+var ErrConcurrencyConflict = repositories.ErrConcurrencyConflict
+
 type CommandHandler[T, S, P, K any] interface {
 	Handle(
 		ctx context.Context,
 		cmd commands.Command[T],
 		aggregate entities.AggregateProvider[T, S, P, K],
 	) error
+	Execute(
+		ctx context.Context,
+		cmd commands.Command[T],
+		aggregate entities.AggregateProvider[T, S, P, K],
+	) (entities.AggregateProvider[T, S, P, K], error)
+}
+
+type CommandHandlerOption[T, S, P, K, E any] func(*commandHandler[T, S, P, K, E])
+
+func WithRetry[T, S, P, K, E any](
+	policy RetryPolicy,
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+) CommandHandlerOption[T, S, P, K, E] {
+	return func(ch *commandHandler[T, S, P, K, E]) {
+		ch.retry = policy
+		ch.providerFn = providerFn
+	}
+}
+
+func WithIdempotencyRetention[T, S, P, K, E any](
+	retention time.Duration,
+) CommandHandlerOption[T, S, P, K, E] {
+	return func(ch *commandHandler[T, S, P, K, E]) {
+		ch.retention = retention
+	}
 }
 
 type commandHandler[T, S, P, K, E any] struct {
-	store repositories.EventStore[T, S, E]
-	saver repositories.ProjectionStore[P]
+	store      repositories.EventStore[T, S, E]
+	save       func(ctx context.Context, projection P, executor E) error
+	retry      RetryPolicy
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K]
+	retention  time.Duration
+	aggregates *aggregateRepository[T, S, P, K, E]
 }
 
 func NewCommandHandler[T, S, P, K, E any](
 	store repositories.EventStore[T, S, E],
 	saver repositories.ProjectionStore[P],
+	opts ...CommandHandlerOption[T, S, P, K, E],
 ) CommandHandler[T, S, P, K] {
-	return &commandHandler[T, S, P, K, E]{store: store, saver: saver}
+	return newCommandHandler(
+		store,
+		func(ctx context.Context, projection P, _ E) error {
+			return saver.Save(ctx, projection)
+		},
+		opts...,
+	)
+}
+
+func NewTransactionalCommandHandler[T, S, P, K, E any](
+	store repositories.EventStore[T, S, E],
+	saver repositories.TransactionalProjectionStore[P, E],
+	opts ...CommandHandlerOption[T, S, P, K, E],
+) CommandHandler[T, S, P, K] {
+	return newCommandHandler(store, saver.Save, opts...)
+}
+
+func newCommandHandler[T, S, P, K, E any](
+	store repositories.EventStore[T, S, E],
+	save func(ctx context.Context, projection P, executor E) error,
+	opts ...CommandHandlerOption[T, S, P, K, E],
+) *commandHandler[T, S, P, K, E] {
+	ch := &commandHandler[T, S, P, K, E]{
+		store:      store,
+		save:       save,
+		retention:  DefaultIdempotencyRetention,
+		aggregates: newAggregateRepository[T, S, P, K](store),
+	}
+	for _, opt := range opts {
+		opt(ch)
+	}
+	return ch
 }
 
 func (ch *commandHandler[T, S, P, K, E]) Handle(
 	ctx context.Context,
 	cmd commands.Command[T],
 	aggregate entities.AggregateProvider[T, S, P, K],
+) error {
+	_, err := ch.Execute(ctx, cmd, aggregate)
+	return err
+}
+
+func (ch *commandHandler[T, S, P, K, E]) Execute(
+	ctx context.Context,
+	cmd commands.Command[T],
+	aggregate entities.AggregateProvider[T, S, P, K],
+) (entities.AggregateProvider[T, S, P, K], error) {
+	err := ch.handle(ctx, cmd, aggregate)
+	for attempt := 1; attempt < ch.retry.MaxAttempts && ch.providerFn != nil; attempt++ {
+		if !errors.Is(err, ErrConcurrencyConflict) {
+			break
+		}
+		if waitErr := ch.retry.Wait(ctx, attempt); waitErr != nil {
+			return aggregate, errors.Join(err, waitErr)
+		}
+		aggregate = ch.providerFn(aggregate.ID())
+		err = ch.handle(ctx, cmd, aggregate)
+	}
+	if errors.Is(err, repositories.ErrDuplicateCommand) {
+		return aggregate, nil
+	}
+	return aggregate, err
+}
+
+func (ch *commandHandler[T, S, P, K, E]) handle(
+	ctx context.Context,
+	cmd commands.Command[T],
+	aggregate entities.AggregateProvider[T, S, P, K],
 ) (err error) {
 	commitExecutor, beginErr := ch.store.Begin(ctx)
 	if beginErr != nil {
@@ -43,65 +142,65 @@ func (ch *commandHandler[T, S, P, K, E]) Handle(
 		if err != nil {
 			rollbackErr := ch.store.Rollback(ctx, commitExecutor)
 			if rollbackErr != nil {
-				err = rollbackErr
+				err = errors.Join(err, rollbackErr)
 			}
 		} else {
 			err = ch.store.Commit(ctx, commitExecutor)
 		}
 	}()
-	err = ch.changeAggregate(ctx, cmd, uuid.New(), aggregate, commitExecutor)
+	err = ch.changeAggregate(ctx, cmd, aggregate, commitExecutor)
 	if err != nil {
 		return err
 	}
-	return ch.saver.Save(ctx, aggregate.Projection())
+	return ch.save(ctx, aggregate.Projection(), commitExecutor)
 }
 
 func (ch *commandHandler[T, S, P, K, E]) changeAggregate(
 	ctx context.Context,
 	cmd commands.Command[T],
-	transactionID uuid.UUID,
 	aggregate entities.AggregateProvider[T, S, P, K],
 	commitExecutor E,
 ) error {
-	version, payload, err := ch.store.GetSnapshot(ctx, aggregate.ID(), nil, commitExecutor)
-	if err != nil {
-		return err
-	}
-	var currentVersion int
-	if version == 0 {
-		currentVersion = -1
-	} else {
-		currentVersion = version
-		if err = aggregate.BuildFromSnapshot(version, payload); err != nil {
+	if cmd.ID != "" {
+		handled, err := ch.store.HasCommand(
+			ctx,
+			cmd.ID,
+			time.Now().Add(-ch.retention),
+			commitExecutor,
+		)
+		if err != nil {
 			return err
 		}
+		if handled {
+			return repositories.ErrDuplicateCommand
+		}
 	}
-
-	var history []events.Event[T]
-	history, err = ch.store.GetEvents(ctx, aggregate.ID(), currentVersion+1, nil, commitExecutor)
+	err := ch.aggregates.load(ctx, aggregate, nil, commitExecutor)
 	if err != nil {
 		return err
 	}
-	if err = aggregate.Build(history); err != nil {
+	if err = cmd.CheckPreconditions(aggregate.Version()); err != nil {
 		return err
 	}
+	transaction := transactions.NewTransaction(uuid.New(), aggregate.ID(), 0)
+	transaction.CommandID = cmd.ID
 	newEvents := make([]events.Event[T], len(cmd.Events))
 	for i, event := range cmd.Events {
 		newEvents[i] = events.NewEvent[T](
-			aggregate.ID(),
-			transactionID,
+			transaction.AggregateID,
+			transaction.ID,
 			cmd.Type,
 			aggregate.Version()+i+1,
 			event.Type,
 			event.Payload,
-		)
+		).WithMetadata(cmd.Metadata)
 	}
 	if err = aggregate.ApplyChanges(newEvents); err != nil {
 		return err
 	}
 	return ch.store.UpdateOrCreateAggregate(
 		ctx,
-		transactionID,
+		transaction,
 		aggregate,
 		aggregate.Snapshot(),
 		commitExecutor,
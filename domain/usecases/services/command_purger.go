@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+)
+
+type CommandPurger interface {
+	Purge(ctx context.Context) (int64, error)
+}
+
+type CommandPurgerOption[T, S, E any] func(*commandPurger[T, S, E])
+
+func WithPurgeRetention[T, S, E any](retention time.Duration) CommandPurgerOption[T, S, E] {
+	return func(p *commandPurger[T, S, E]) {
+		p.retention = retention
+	}
+}
+
+type commandPurger[T, S, E any] struct {
+	store     repositories.EventStore[T, S, E]
+	retention time.Duration
+}
+
+func NewCommandPurger[T, S, E any](
+	store repositories.EventStore[T, S, E],
+	opts ...CommandPurgerOption[T, S, E],
+) CommandPurger {
+	p := &commandPurger[T, S, E]{
+		store:     store,
+		retention: DefaultIdempotencyRetention,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *commandPurger[T, S, E]) Purge(ctx context.Context) (int64, error) {
+	executor, err := p.store.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := p.store.PurgeCommands(ctx, time.Now().Add(-p.retention), executor)
+	if err != nil {
+		if rollbackErr := p.store.Rollback(ctx, executor); rollbackErr != nil {
+			return 0, errors.Join(err, rollbackErr)
+		}
+		return 0, err
+	}
+	if err = p.store.Commit(ctx, executor); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -5,40 +5,80 @@ import (
 
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 )
 
-type EventHandler[T, S, P, K any] interface {
+type EventHandler[T, S, P, K, E any] interface {
 	HandleEvents(
 		ctx context.Context,
 		provider entities.AggregateProvider[T, S, P, K],
 		events []events.Event[T],
+		executor E,
 	) error
 }
 
-type eventHandler[T, S, P, K any] struct {
+type eventHandler[T, S, P, K, E any] struct {
 	publisher repositories.Publisher[K]
 }
 
-func NewEventHandler[T, S, P, K any](publisher repositories.Publisher[K]) EventHandler[T, S, P, K] {
-	return &eventHandler[T, S, P, K]{publisher: publisher}
+func NewEventHandler[T, S, P, K, E any](publisher repositories.Publisher[K]) EventHandler[T, S, P, K, E] {
+	return &eventHandler[T, S, P, K, E]{publisher: publisher}
 }
 
-func (eh *eventHandler[T, S, P, K]) HandleEvents(
+func (eh *eventHandler[T, S, P, K, E]) HandleEvents(
 	ctx context.Context,
 	provider entities.AggregateProvider[T, S, P, K],
 	newEvents []events.Event[T],
+	_ E,
 ) error {
+	integrationEvents, err := applyEvents(provider, newEvents)
+	if err != nil {
+		return err
+	}
+	return eh.publisher.Publish(ctx, integrationEvents)
+}
+
+type outboxEventHandler[T, S, P, K, E any] struct {
+	outbox repositories.OutboxStore[K, E]
+}
+
+func NewOutboxEventHandler[T, S, P, K, E any](
+	outbox repositories.OutboxStore[K, E],
+) EventHandler[T, S, P, K, E] {
+	return &outboxEventHandler[T, S, P, K, E]{outbox: outbox}
+}
+
+func (eh *outboxEventHandler[T, S, P, K, E]) HandleEvents(
+	ctx context.Context,
+	provider entities.AggregateProvider[T, S, P, K],
+	newEvents []events.Event[T],
+	executor E,
+) error {
+	integrationEvents, err := applyEvents(provider, newEvents)
+	if err != nil {
+		return err
+	}
+	messages := make([]*outbox.Message[K], 0, len(integrationEvents))
+	for i, integrationEvent := range integrationEvents {
+		messages = append(messages, outbox.NewMessage(newEvents[i].AggregateID, integrationEvent))
+	}
+	return eh.outbox.Enqueue(ctx, messages, executor)
+}
+
+func applyEvents[T, S, P, K any](
+	provider entities.AggregateProvider[T, S, P, K],
+	newEvents []events.Event[T],
+) ([]events.IntegrationEvent[K], error) {
 	integrationEvents := make([]events.IntegrationEvent[K], 0)
 	for _, event := range newEvents {
 		err := provider.ApplyChange(event)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		integrationEvents = append(
-			integrationEvents,
-			provider.IntegrationEvent(event.Type),
-		)
+		integrationEvent := provider.IntegrationEvent(event.Type)
+		integrationEvent.Metadata = event.Metadata
+		integrationEvents = append(integrationEvents, integrationEvent)
 	}
-	return eh.publisher.Publish(ctx, integrationEvents)
+	return integrationEvents, nil
 }
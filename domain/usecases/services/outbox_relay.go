@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+)
+
+const DefaultOutboxMaxAttempts = 10
+
+type OutboxRelay interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+type OutboxRelayOption[K, E any] func(*outboxRelay[K, E])
+
+func WithOutboxBatchSize[K, E any](batchSize int) OutboxRelayOption[K, E] {
+	return func(r *outboxRelay[K, E]) {
+		r.batchSize = batchSize
+	}
+}
+
+func WithOutboxMaxAttempts[K, E any](maxAttempts int) OutboxRelayOption[K, E] {
+	return func(r *outboxRelay[K, E]) {
+		r.maxAttempts = maxAttempts
+	}
+}
+
+type outboxRelay[K, E any] struct {
+	committer   repositories.TFACommitter[E]
+	outbox      repositories.OutboxStore[K, E]
+	publisher   repositories.Publisher[K]
+	batchSize   int
+	maxAttempts int
+	log         *slog.Logger
+}
+
+func NewOutboxRelay[K, E any](
+	committer repositories.TFACommitter[E],
+	outbox repositories.OutboxStore[K, E],
+	publisher repositories.Publisher[K],
+	log *slog.Logger,
+	opts ...OutboxRelayOption[K, E],
+) OutboxRelay {
+	r := &outboxRelay[K, E]{
+		committer:   committer,
+		outbox:      outbox,
+		publisher:   publisher,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: DefaultOutboxMaxAttempts,
+		log:         log,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *outboxRelay[K, E]) Relay(ctx context.Context) (int, error) {
+	commitExecutor, err := r.committer.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	messages, err := r.outbox.GetPending(ctx, r.batchSize, commitExecutor)
+	if err != nil {
+		return 0, r.rollback(ctx, err, commitExecutor)
+	}
+	publishErrs, err := r.publish(ctx, messages, commitExecutor)
+	if err != nil {
+		return 0, r.rollback(ctx, err, commitExecutor)
+	}
+	if err = r.committer.Commit(ctx, commitExecutor); err != nil {
+		return 0, err
+	}
+	return len(messages), errors.Join(publishErrs...)
+}
+
+func (r *outboxRelay[K, E]) publish(
+	ctx context.Context,
+	messages []*outbox.Message[K],
+	commitExecutor E,
+) ([]error, error) {
+	order := make([]uuid.UUID, 0)
+	groups := make(map[uuid.UUID][]*outbox.Message[K])
+	for _, message := range messages {
+		if _, ok := groups[message.AggregateID]; !ok {
+			order = append(order, message.AggregateID)
+		}
+		groups[message.AggregateID] = append(groups[message.AggregateID], message)
+	}
+	var publishErrs []error
+	sent := make([]int64, 0, len(messages))
+	for _, aggregateID := range order {
+		group := groups[aggregateID]
+		ids := make([]int64, 0, len(group))
+		integrationEvents := make([]events.IntegrationEvent[K], 0, len(group))
+		for _, message := range group {
+			ids = append(ids, message.ID)
+			integrationEvents = append(integrationEvents, message.Event)
+		}
+		if err := r.publisher.Publish(ctx, integrationEvents); err != nil {
+			r.log.ErrorContext(
+				ctx,
+				err.Error(),
+				slog.String("aggregate_id", aggregateID.String()),
+				slog.Int("attempts", group[0].Attempts+1),
+			)
+			publishErrs = append(publishErrs, err)
+			if err = r.fail(ctx, group, err.Error(), commitExecutor); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sent = append(sent, ids...)
+	}
+	if len(sent) > 0 {
+		if err := r.outbox.MarkSent(ctx, sent, commitExecutor); err != nil {
+			return nil, err
+		}
+	}
+	return publishErrs, nil
+}
+
+func (r *outboxRelay[K, E]) fail(
+	ctx context.Context,
+	group []*outbox.Message[K],
+	reason string,
+	commitExecutor E,
+) error {
+	ids := make([]int64, 0, len(group))
+	exhausted := false
+	for _, message := range group {
+		ids = append(ids, message.ID)
+		exhausted = exhausted || r.maxAttempts > 0 && message.Attempts+1 >= r.maxAttempts
+	}
+	if !exhausted {
+		return r.outbox.MarkFailed(ctx, ids, reason, commitExecutor)
+	}
+	r.log.WarnContext(
+		ctx,
+		"outbox messages parked after max attempts",
+		slog.String("aggregate_id", group[0].AggregateID.String()),
+		slog.Int("max_attempts", r.maxAttempts),
+		slog.Int("parked", len(ids)),
+	)
+	return r.outbox.Park(ctx, ids, reason, commitExecutor)
+}
+
+func (r *outboxRelay[K, E]) rollback(ctx context.Context, err error, commitExecutor E) error {
+	if rollbackErr := r.committer.Rollback(ctx, commitExecutor); rollbackErr != nil {
+		return errors.Join(err, rollbackErr)
+	}
+	return err
+}
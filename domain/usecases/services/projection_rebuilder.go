@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+)
+
+const DefaultRebuildWorkers = 4
+
+type RebuildProgress struct {
+	Processed int
+	Cursor    uuid.UUID
+}
+
+type RebuildOptions struct {
+	IDs           []uuid.UUID
+	AggregateType string
+	After         uuid.UUID
+	Workers       int
+	PageSize      int
+	Progress      func(progress RebuildProgress)
+}
+
+type ProjectionRebuilder interface {
+	Rebuild(ctx context.Context, opts RebuildOptions) (RebuildProgress, error)
+}
+
+type ProjectionRebuilderOption[T, S, P, K, E any] func(*projectionRebuilder[T, S, P, K, E])
+
+func WithRebuildAggregateRegistry[T, S, P, K, E any](
+	registry *entities.AggregateRegistry[T, S, P, K],
+) ProjectionRebuilderOption[T, S, P, K, E] {
+	return func(r *projectionRebuilder[T, S, P, K, E]) {
+		r.registry = registry
+	}
+}
+
+type projectionRebuilder[T, S, P, K, E any] struct {
+	store      repositories.EventStore[T, S, E]
+	saver      repositories.ProjectionStore[P]
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K]
+	registry   *entities.AggregateRegistry[T, S, P, K]
+	aggregates *aggregateRepository[T, S, P, K, E]
+}
+
+func NewProjectionRebuilder[T, S, P, K, E any](
+	store repositories.EventStore[T, S, E],
+	saver repositories.ProjectionStore[P],
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+	opts ...ProjectionRebuilderOption[T, S, P, K, E],
+) ProjectionRebuilder {
+	r := &projectionRebuilder[T, S, P, K, E]{
+		store:      store,
+		saver:      saver,
+		providerFn: providerFn,
+		aggregates: newAggregateRepository[T, S, P, K](store),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *projectionRebuilder[T, S, P, K, E]) Rebuild(
+	ctx context.Context,
+	opts RebuildOptions,
+) (RebuildProgress, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultRebuildWorkers
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultBatchSize
+	}
+	progress := RebuildProgress{Cursor: opts.After}
+	ids := slices.SortedFunc(slices.Values(opts.IDs), compareIDs)
+	for {
+		page, types, err := r.nextPage(ctx, opts, ids, progress.Cursor)
+		if err != nil || len(page) == 0 {
+			return progress, err
+		}
+		if err = r.rebuildPage(ctx, page, types, opts.Workers); err != nil {
+			return progress, err
+		}
+		progress.Processed += len(page)
+		progress.Cursor = page[len(page)-1]
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+}
+
+func (r *projectionRebuilder[T, S, P, K, E]) nextPage(
+	ctx context.Context,
+	opts RebuildOptions,
+	ids []uuid.UUID,
+	cursor uuid.UUID,
+) ([]uuid.UUID, map[uuid.UUID]string, error) {
+	var page []uuid.UUID
+	if len(opts.IDs) > 0 {
+		start, _ := slices.BinarySearchFunc(ids, cursor, compareIDs)
+		for start < len(ids) && ids[start] == cursor {
+			start++
+		}
+		page = ids[start:min(start+opts.PageSize, len(ids))]
+		if r.registry == nil || len(page) == 0 {
+			return page, nil, nil
+		}
+	}
+	commitExecutor, err := r.store.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(opts.IDs) == 0 {
+		page, err = r.store.GetAggregateIDs(ctx, cursor, opts.PageSize, opts.AggregateType, commitExecutor)
+	}
+	var types map[uuid.UUID]string
+	if err == nil && r.registry != nil && len(page) > 0 {
+		types, err = r.store.GetAggregateTypes(ctx, page, commitExecutor)
+	}
+	if rollbackErr := r.store.Rollback(ctx, commitExecutor); err == nil {
+		err = rollbackErr
+	}
+	return page, types, err
+}
+
+func (r *projectionRebuilder[T, S, P, K, E]) rebuildPage(
+	ctx context.Context,
+	page []uuid.UUID,
+	types map[uuid.UUID]string,
+	workers int,
+) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	ids := make(chan uuid.UUID, len(page))
+	for _, id := range page {
+		ids <- id
+	}
+	close(ids)
+	var wg sync.WaitGroup
+	for range min(workers, len(page)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if ctx.Err() != nil {
+					return
+				}
+				if err := r.rebuildOne(ctx, id, types[id]); err != nil {
+					cancel(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+func (r *projectionRebuilder[T, S, P, K, E]) rebuildOne(ctx context.Context, id uuid.UUID, aggregateType string) error {
+	aggregate, err := r.provider(id, aggregateType)
+	if err != nil {
+		return err
+	}
+	if err = r.aggregates.Load(ctx, aggregate); err != nil {
+		return err
+	}
+	return r.saver.Save(ctx, aggregate.Projection())
+}
+
+func (r *projectionRebuilder[T, S, P, K, E]) provider(
+	id uuid.UUID,
+	aggregateType string,
+) (entities.AggregateProvider[T, S, P, K], error) {
+	if r.providerFn != nil && (r.registry == nil || aggregateType == "") {
+		return r.providerFn(id), nil
+	}
+	if r.registry == nil {
+		return nil, fmt.Errorf("%w: %q", entities.ErrUnknownAggregateType, aggregateType)
+	}
+	return r.registry.Provider(aggregateType, id)
+}
+
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/projections"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+)
+
+type Projector interface {
+	Project(ctx context.Context) error
+	Lag(ctx context.Context) ([]projections.Lag, error)
+}
+
+type ProjectorOption[T, S, E any] func(*projector[T, S, E])
+
+func WithProjectorBatchSize[T, S, E any](batchSize int) ProjectorOption[T, S, E] {
+	return func(p *projector[T, S, E]) {
+		p.batchSize = batchSize
+	}
+}
+
+type projector[T, S, E any] struct {
+	eventStore  repositories.EventStore[T, S, E]
+	projections []*projections.Projection[T, E]
+	batchSize   int
+	log         *slog.Logger
+}
+
+func NewProjector[T, S, E any](
+	store repositories.EventStore[T, S, E],
+	projections []*projections.Projection[T, E],
+	log *slog.Logger,
+	opts ...ProjectorOption[T, S, E],
+) Projector {
+	p := &projector[T, S, E]{
+		eventStore:  store,
+		projections: projections,
+		batchSize:   DefaultBatchSize,
+		log:         log,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *projector[T, S, E]) Project(ctx context.Context) error {
+	errs := make([]error, len(p.projections))
+	var wg sync.WaitGroup
+	for i, projection := range p.projections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.catchUp(ctx, projection)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *projector[T, S, E]) Lag(ctx context.Context) ([]projections.Lag, error) {
+	commitExecutor, err := p.eventStore.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = p.eventStore.Rollback(ctx, commitExecutor)
+	}()
+	subs, err := p.eventStore.GetSubscriptions(ctx, commitExecutor)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[string]int64, len(subs))
+	for _, sub := range subs {
+		positions[sub.Name] = sub.LastSequenceID
+	}
+	result := make([]projections.Lag, 0, len(p.projections))
+	for _, projection := range p.projections {
+		position := positions[projection.Checkpoint()]
+		pending, countErr := p.eventStore.CountTransactions(ctx, position, commitExecutor)
+		if countErr != nil {
+			return nil, countErr
+		}
+		result = append(result, projections.NewLag(projection.Name(), position, pending))
+	}
+	return result, nil
+}
+
+func (p *projector[T, S, E]) catchUp(ctx context.Context, projection *projections.Projection[T, E]) error {
+	loop := catchUpLoop[T, S, E]{
+		eventStore: p.eventStore,
+		checkpoint: projection.Checkpoint(),
+		batchSize:  p.batchSize,
+		log:        p.log.With(slog.String("projection", projection.Name())),
+	}
+	return loop.run(
+		ctx,
+		func(ctx context.Context, transaction *transactions.Transaction, lastSequenceID int64, commitExecutor E) error {
+			newEvents, err := p.eventStore.GetUnhandledEvents(
+				ctx,
+				transaction.AggregateID,
+				lastSequenceID,
+				transaction.SequenceID,
+				commitExecutor,
+			)
+			if err != nil {
+				return err
+			}
+			for _, event := range newEvents {
+				if err = projection.Handle(ctx, event, commitExecutor); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
@@ -3,131 +3,216 @@ package services
 import (
 	"cmp"
 	"context"
+	"errors"
 	"log/slog"
 	"slices"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
-	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
 	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 	"github.com/google/uuid"
 )
 
+const DefaultBatchSize = 100
+
+var ErrAggregateProviderMissing = errors.New("aggregate provider function or registry is required")
+
 type TransactionHandler[T, S, P, K, E any] interface {
 	Handle(
 		ctx context.Context,
 		transaction *transactions.Transaction,
 		providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
 	) error
+	CatchUp(
+		ctx context.Context,
+		providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+	) error
+	HandleTransaction(
+		ctx context.Context,
+		transaction *transactions.Transaction,
+		providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+	) error
+}
+
+type TransactionHandlerOption[T, S, P, K, E any] func(*transactionHandler[T, S, P, K, E])
+
+func WithBatchSize[T, S, P, K, E any](batchSize int) TransactionHandlerOption[T, S, P, K, E] {
+	return func(eh *transactionHandler[T, S, P, K, E]) {
+		eh.batchSize = batchSize
+	}
+}
+
+func WithAggregateRegistry[T, S, P, K, E any](
+	registry *entities.AggregateRegistry[T, S, P, K],
+) TransactionHandlerOption[T, S, P, K, E] {
+	return func(eh *transactionHandler[T, S, P, K, E]) {
+		eh.registry = registry
+	}
 }
 
 type transactionHandler[T, S, P, K, E any] struct {
 	eventStore   repositories.EventStore[T, S, E]
-	eventHandler EventHandler[T, S, P, K]
+	eventHandler EventHandler[T, S, P, K, E]
+	subscription string
+	batchSize    int
+	registry     *entities.AggregateRegistry[T, S, P, K]
+	aggregates   *aggregateRepository[T, S, P, K, E]
 	log          *slog.Logger
 }
 
 func NewTransactionHandler[T, S, P, K, E any](
 	store repositories.EventStore[T, S, E],
-	eventHandler EventHandler[T, S, P, K],
+	eventHandler EventHandler[T, S, P, K, E],
+	subscription string,
 	log *slog.Logger,
+	opts ...TransactionHandlerOption[T, S, P, K, E],
 ) TransactionHandler[T, S, P, K, E] {
-	return &transactionHandler[T, S, P, K, E]{
+	eh := &transactionHandler[T, S, P, K, E]{
 		eventStore:   store,
 		eventHandler: eventHandler,
+		subscription: subscription,
+		batchSize:    DefaultBatchSize,
+		aggregates:   newAggregateRepository[T, S, P, K](store),
 		log:          log,
 	}
+	for _, opt := range opts {
+		opt(eh)
+	}
+	return eh
 }
 
 func (eh *transactionHandler[T, S, P, K, E]) Handle(
 	ctx context.Context,
 	transaction *transactions.Transaction,
 	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
-) (err error) {
-	commitExecutor, beginErr := eh.eventStore.Begin(ctx)
-	if beginErr != nil {
-		return beginErr
-	}
-	defer func() {
-		if err != nil {
-			commitErr := eh.eventStore.Rollback(ctx, commitExecutor)
-			if commitErr != nil {
-				err = commitErr
-			}
-		} else {
-			err = eh.eventStore.Commit(ctx, commitExecutor)
-		}
-	}()
-	sub, err := eh.eventStore.GetSubscription(ctx, commitExecutor)
+) error {
+	if transaction != nil {
+		eh.log.DebugContext(
+			ctx,
+			"transaction notification received",
+			slog.String("transaction_id", transaction.ID.String()),
+			slog.Int64("sequence_id", transaction.SequenceID),
+		)
+	}
+	return eh.CatchUp(ctx, providerFn)
+}
+
+func (eh *transactionHandler[T, S, P, K, E]) CatchUp(
+	ctx context.Context,
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+) error {
+	if providerFn == nil && eh.registry == nil {
+		return ErrAggregateProviderMissing
+	}
+	loop := catchUpLoop[T, S, E]{
+		eventStore: eh.eventStore,
+		checkpoint: eh.subscription,
+		batchSize:  eh.batchSize,
+		log:        eh.log,
+	}
+	return loop.run(
+		ctx,
+		func(ctx context.Context, transaction *transactions.Transaction, lastSequenceID int64, commitExecutor E) error {
+			return eh.handleTransaction(ctx, transaction, lastSequenceID, providerFn, commitExecutor)
+		},
+	)
+}
+
+func (eh *transactionHandler[T, S, P, K, E]) HandleTransaction(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+) error {
+	if providerFn == nil && eh.registry == nil {
+		return ErrAggregateProviderMissing
+	}
+	commitExecutor, err := eh.eventStore.Begin(ctx)
 	if err != nil {
-		eh.log.ErrorContext(ctx, err.Error())
 		return err
 	}
+	if eh.registry != nil && transaction.AggregateType == "" {
+		transaction, err = eh.withAggregateType(ctx, transaction, commitExecutor)
+	}
+	if err == nil {
+		err = eh.handleTransaction(ctx, transaction, transaction.SequenceID-1, providerFn, commitExecutor)
+	}
+	if err != nil {
+		if rollbackErr := eh.eventStore.Rollback(ctx, commitExecutor); rollbackErr != nil {
+			err = errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return eh.eventStore.Commit(ctx, commitExecutor)
+}
+
+func (eh *transactionHandler[T, S, P, K, E]) withAggregateType(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	commitExecutor E,
+) (*transactions.Transaction, error) {
+	types, err := eh.eventStore.GetAggregateTypes(ctx, []uuid.UUID{transaction.AggregateID}, commitExecutor)
+	if err != nil {
+		return transaction, err
+	}
+	typed := *transaction
+	typed.AggregateType = types[transaction.AggregateID]
+	return &typed, nil
+}
+
+func (eh *transactionHandler[T, S, P, K, E]) handleTransaction(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	lastSequenceID int64,
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+	commitExecutor E,
+) error {
 	newEvents, err := eh.eventStore.GetUnhandledEvents(
 		ctx,
 		transaction.AggregateID,
-		sub.LastSequenceID,
+		lastSequenceID,
 		transaction.SequenceID,
 		commitExecutor,
 	)
 	if err != nil {
-		eh.log.ErrorContext(ctx, err.Error())
 		return err
 	}
+	if len(newEvents) == 0 {
+		return nil
+	}
 	firstNxtVersion := slices.MinFunc(newEvents, func(a, b events.Event[T]) int {
 		return cmp.Compare(a.Version, b.Version)
 	}).Version
 
-	provider := providerFn(transaction.AggregateID)
-
-	version, payload, err := eh.eventStore.GetSnapshot(
-		ctx,
-		provider.ID(),
-		&firstNxtVersion,
-		commitExecutor,
-	)
+	provider, err := eh.provider(transaction, providerFn)
+	if errors.Is(err, entities.ErrUnknownAggregateType) {
+		eh.log.WarnContext(
+			ctx,
+			"transaction skipped",
+			slog.String("error", err.Error()),
+			slog.String("transaction_id", transaction.ID.String()),
+			slog.Int64("sequence_id", transaction.SequenceID),
+		)
+		return nil
+	}
 	if err != nil {
-		eh.log.ErrorContext(ctx, err.Error())
 		return err
 	}
-	var currentVersion int
-	if version == 0 {
-		currentVersion = -1
-	} else {
-		currentVersion = version
-		if err = provider.BuildFromSnapshot(version, payload); err != nil {
-			return err
-		}
-	}
+
 	lastVersion := firstNxtVersion - 1
-	if lastVersion > currentVersion {
-		var history []events.Event[T]
-		history, err = eh.eventStore.GetEvents(
-			ctx,
-			provider.ID(),
-			currentVersion+1,
-			&lastVersion,
-			commitExecutor,
-		)
-		if err != nil {
-			return err
-		}
-		err = provider.Build(history)
-		if err != nil {
-			eh.log.ErrorContext(ctx, err.Error())
-			return err
-		}
-	}
-	err = eh.eventHandler.HandleEvents(ctx, provider, newEvents)
+	err = eh.aggregates.load(ctx, provider, &lastVersion, commitExecutor)
 	if err != nil {
-		eh.log.ErrorContext(ctx, err.Error())
 		return err
 	}
+	return eh.eventHandler.HandleEvents(ctx, provider, newEvents, commitExecutor)
+}
 
-	return eh.eventStore.UpdateSubscription(
-		ctx,
-		&subscriptions.Subscription{LastSequenceID: transaction.SequenceID},
-		commitExecutor,
-	)
+func (eh *transactionHandler[T, S, P, K, E]) provider(
+	transaction *transactions.Transaction,
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+) (entities.AggregateProvider[T, S, P, K], error) {
+	if eh.registry == nil || (transaction.AggregateType == "" && providerFn != nil) {
+		return providerFn(transaction.AggregateID), nil
+	}
+	return eh.registry.Provider(transaction.AggregateType, transaction.AggregateID)
 }
@@ -0,0 +1,358 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/mocks/entities"
+	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type AggregateRepositoryTestCase struct {
+	description   string
+	ctx           context.Context
+	mockAssertion func(tc AggregateRepositoryTestCase)
+	dataAssertion func(actual error)
+}
+
+func TestAggregateRepository_LoadMethod(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		providerMock     *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected      = errors.New("test error")
+		expectedExecutor = &struct{}{}
+		expectedID       = uuid.New()
+		expectedSnapshot = &struct{}{}
+		expectedHistory  = []events.Event[*struct{}]{{AggregateID: expectedID, Version: 4}}
+	)
+	testCases := []AggregateRepositoryTestCase{
+		{
+			description: "При вызове метода Load агрегат должен собираться из последнего снимка и следующих за ним событий",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(2)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(3, expectedSnapshot, nil)
+				providerMock.EXPECT().BuildFromSnapshot(3, expectedSnapshot).Return(nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 4, func() *int { return nil }(), expectedExecutor).
+					Return(expectedHistory, nil)
+				providerMock.EXPECT().Build(expectedHistory).Return(nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Load не удалось получить события, то должна вернуться ошибка, а транзакция должна откатиться", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(2)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(nil, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Load не удалось начать транзакцию, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(nil, errExpected)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				providerMock = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](t)
+				tc.mockAssertion(tc)
+
+				repository := services.NewAggregateRepository[*struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+				)
+				err := repository.Load(tc.ctx, providerMock)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestAggregateRepository_LoadAtVersionMethod(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		providerMock     *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		expectedExecutor = &struct{}{}
+		expectedID       = uuid.New()
+		expectedSnapshot = &struct{}{}
+		expectedHistory  = []events.Event[*struct{}]{{AggregateID: expectedID, Version: 2}}
+		ptr              = func(v int) *int { return &v }
+	)
+	testCases := []AggregateRepositoryTestCase{
+		{
+			description: "При вызове метода LoadAtVersion агрегат должен собираться из снимка не старше версии и событий до этой версии включительно", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(2)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, ptr(3), expectedExecutor).
+					Return(1, expectedSnapshot, nil)
+				providerMock.EXPECT().BuildFromSnapshot(1, expectedSnapshot).Return(nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 2, ptr(2), expectedExecutor).
+					Return(expectedHistory, nil)
+				providerMock.EXPECT().Build(expectedHistory).Return(nil)
+				providerMock.EXPECT().Version().Return(2)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если снимок при вызове метода LoadAtVersion совпадает с версией, то события не должны запрашиваться",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Once()
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, ptr(3), expectedExecutor).
+					Return(2, expectedSnapshot, nil)
+				providerMock.EXPECT().BuildFromSnapshot(2, expectedSnapshot).Return(nil)
+				providerMock.EXPECT().Version().Return(2)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода LoadAtVersion агрегат не достиг запрошенной версии, то должна вернуться ошибка ErrAggregateVersionNotFound", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(3)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, ptr(3), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, ptr(2), expectedExecutor).
+					Return(expectedHistory[:0], nil)
+				providerMock.EXPECT().Build(expectedHistory[:0]).Return(nil)
+				providerMock.EXPECT().Version().Return(0)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, services.ErrAggregateVersionNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				providerMock = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](t)
+				tc.mockAssertion(tc)
+
+				repository := services.NewAggregateRepository[*struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+				)
+				err := repository.LoadAtVersion(tc.ctx, providerMock, 2)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestAggregateRepository_LoadAsOfMethod(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		providerMock     *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected      = errors.New("test error")
+		expectedExecutor = &struct{}{}
+		expectedID       = uuid.New()
+		expectedSnapshot = &struct{}{}
+		expectedAsOf     = time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC)
+		expectedHistory  = []events.Event[*struct{}]{{AggregateID: expectedID, Version: 3}}
+	)
+	testCases := []AggregateRepositoryTestCase{
+		{
+			description: "При вызове метода LoadAsOf агрегат должен собираться из снимка и событий, созданных не позже указанного момента", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(2)
+				eventStoreMock.EXPECT().
+					GetSnapshotAsOf(tc.ctx, expectedID, expectedAsOf, expectedExecutor).
+					Return(2, expectedSnapshot, nil)
+				providerMock.EXPECT().BuildFromSnapshot(2, expectedSnapshot).Return(nil)
+				eventStoreMock.EXPECT().
+					GetEventsUntil(tc.ctx, expectedID, 3, expectedAsOf, expectedExecutor).
+					Return(expectedHistory, nil)
+				providerMock.EXPECT().Build(expectedHistory).Return(nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если до указанного момента снимков не было, то при вызове метода LoadAsOf события должны читаться с начала", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Times(2)
+				eventStoreMock.EXPECT().
+					GetSnapshotAsOf(tc.ctx, expectedID, expectedAsOf, expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEventsUntil(tc.ctx, expectedID, 0, expectedAsOf, expectedExecutor).
+					Return(expectedHistory, nil)
+				providerMock.EXPECT().Build(expectedHistory).Return(nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода LoadAsOf не удалось получить снимок, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				providerMock.EXPECT().ID().Return(expectedID).Once()
+				eventStoreMock.EXPECT().
+					GetSnapshotAsOf(tc.ctx, expectedID, expectedAsOf, expectedExecutor).
+					Return(0, nil, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				providerMock = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](t)
+				tc.mockAssertion(tc)
+
+				repository := services.NewAggregateRepository[*struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+				)
+				err := repository.LoadAsOf(tc.ctx, providerMock, expectedAsOf)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestAggregateRepository_ExistsMethod(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		errExpected      = errors.New("test error")
+		expectedExecutor = &struct{}{}
+		expectedID       = uuid.New()
+		firstVersion     = 1
+		exists           bool
+	)
+	testCases := []AggregateRepositoryTestCase{
+		{
+			description: "Если у агрегата есть первое событие, то метод Exists должен вернуть true",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 1, &firstVersion, expectedExecutor).
+					Return([]events.Event[*struct{}]{{AggregateID: expectedID, Version: 1}}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+				assert.True(t, exists)
+			},
+		},
+		{
+			description: "Если у агрегата нет событий, то метод Exists должен вернуть false",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 1, &firstVersion, expectedExecutor).
+					Return([]events.Event[*struct{}]{}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+				assert.False(t, exists)
+			},
+		},
+		{
+			description: "Если при вызове метода Exists не удалось откатить транзакцию, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc AggregateRepositoryTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 1, &firstVersion, expectedExecutor).
+					Return([]events.Event[*struct{}]{}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(errExpected)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				tc.mockAssertion(tc)
+
+				repository := services.NewAggregateRepository[*struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+				)
+				var err error
+				exists, err = repository.Exists(tc.ctx, expectedID)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
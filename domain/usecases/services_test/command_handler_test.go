@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/commands"
+	coreEntities "github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	coreRepositories "github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
 	"github.com/alex-fullstack/event-sourcingo/mocks/entities"
 	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
@@ -96,13 +100,143 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 				assert.Equal(t, errExpected, actual)
 			},
 		},
+		{
+			description: "Если при вызове метода Handle агрегат уже существует, а команда требует его отсутствия, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithNoStream(),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				aggregateProviderMock.EXPECT().Version().Return(1)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, commands.ErrStreamAlreadyExists)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle агрегат не существует, а команда требует его наличия, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithStreamExists(),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				aggregateProviderMock.EXPECT().Version().Return(0)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, commands.ErrStreamNotFound)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle версия агрегата не совпадает с ожидаемой версией команды, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithExpectedVersion(1),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				aggregateProviderMock.EXPECT().Version().Return(2)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, commands.ErrWrongExpectedVersion)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle команда с тем же идентификатором уже была обработана, то новые события не записываются и возвращается пустой результат", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithID("command-id"),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					HasCommand(tc.ctx, "command-id", mock.Anything, expectedExecutor).
+					Return(true, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle не удалось проверить идентификатор команды, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithID("command-id"),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					HasCommand(tc.ctx, "command-id", mock.Anything, expectedExecutor).
+					Return(false, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle команда с тем же идентификатором была записана конкурентно, то возвращается пустой результат с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithID("command-id"),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				eventStoreMock.EXPECT().
+					HasCommand(tc.ctx, "command-id", mock.Anything, expectedExecutor).
+					Return(false, nil)
+				aggregateProviderMock.EXPECT().Version().Return(0)
+				aggregateProviderMock.EXPECT().ApplyChanges(mock.Anything).Return(nil)
+				aggregateProviderMock.EXPECT().Snapshot().Return(expectedSnapshot)
+				eventStoreMock.EXPECT().
+					UpdateOrCreateAggregate(
+						tc.ctx,
+						mock.MatchedBy(func(tx *transactions.Transaction) bool {
+							return tx.CommandID == "command-id" && tx.AggregateID == expectedID
+						}),
+						aggregateProviderMock,
+						mock.Anything,
+						expectedExecutor,
+					).
+					Return(coreRepositories.ErrDuplicateCommand)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
 		{
 			description: "Если при вызове метода Handle не удалось обновить агрегат, то должна вернуться ошибка с откатом транзакции", //nolint:lll
 			ctx:         context.Background(),
 			cmd:         expectedCommand,
 			mockAssertion: func(tc CommandHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
-				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(4)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
 				eventStoreMock.EXPECT().
 					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
 					Return(0, nil, nil)
@@ -124,7 +258,7 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 			cmd:         expectedCommand,
 			mockAssertion: func(tc CommandHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
-				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(4)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
 				eventStoreMock.EXPECT().
 					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
 					Return(0, nil, nil)
@@ -150,13 +284,51 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 				assert.Equal(t, errExpected, actual)
 			},
 		},
+		{
+			description: "Если при вызове метода Handle версия агрегата была изменена конкурентно, то должна вернуться ошибка конфликта с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				aggregateProviderMock.EXPECT().Version().Return(0)
+				aggregateProviderMock.EXPECT().ApplyChanges(mock.Anything).Return(nil)
+				aggregateProviderMock.EXPECT().Snapshot().Return(expectedSnapshot)
+				eventStoreMock.EXPECT().
+					UpdateOrCreateAggregate(
+						tc.ctx,
+						mock.Anything,
+						aggregateProviderMock,
+						mock.Anything,
+						expectedExecutor,
+					).
+					Return(coreRepositories.NewConcurrencyConflictError(expectedID, 2, 3))
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(errExpected)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, services.ErrConcurrencyConflict)
+				assert.ErrorIs(t, actual, errExpected)
+				var conflictErr *coreRepositories.ConcurrencyConflictError
+				if assert.ErrorAs(t, actual, &conflictErr) {
+					assert.Equal(t, 2, conflictErr.ExpectedVersion)
+					assert.Equal(t, 3, conflictErr.ActualVersion)
+				}
+			},
+		},
 		{
 			description: "Если при вызове метода Handle не удалось сохранить проекцию агрегата, то должна вернуться ошибка с откатом транзакции", //nolint:lll
 			ctx:         context.Background(),
 			cmd:         expectedCommand,
 			mockAssertion: func(tc CommandHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
-				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(4)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
 				eventStoreMock.EXPECT().
 					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
 					Return(0, nil, nil)
@@ -190,7 +362,7 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 			cmd:         expectedCommand,
 			mockAssertion: func(tc CommandHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
-				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(4)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
 				eventStoreMock.EXPECT().
 					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
 					Return(0, nil, nil)
@@ -224,7 +396,7 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 			cmd:         expectedCommand,
 			mockAssertion: func(tc CommandHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
-				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(4)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
 				eventStoreMock.EXPECT().
 					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
 					Return(0, nil, nil)
@@ -250,6 +422,37 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 				assert.NoError(t, actual)
 			},
 		},
+		{
+			description: "При вызове метода Handle метаданные команды должны переноситься в новые события агрегата",
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithMetadata(events.NewMetadata("correlation-id", "causation-id", "actor", nil)),
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+					Return(expectedEvents, nil)
+				aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+				aggregateProviderMock.EXPECT().Version().Return(0)
+				aggregateProviderMock.EXPECT().
+					ApplyChanges(mock.MatchedBy(func(newEvents []events.Event[*struct{}]) bool {
+						for _, event := range newEvents {
+							if event.CorrelationID != "correlation-id" || event.Actor != "actor" {
+								return false
+							}
+						}
+						return len(newEvents) == len(tc.cmd.Events)
+					})).
+					Return(errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -277,3 +480,311 @@ func TestCommandHandler_HandleMethod(t *testing.T) {
 			})
 	}
 }
+
+type CommandHandlerRetryTestCase struct {
+	description   string
+	ctx           context.Context
+	cmd           commands.Command[*struct{}]
+	policy        services.RetryPolicy
+	mockAssertion func(tc CommandHandlerRetryTestCase)
+	dataAssertion func(provider coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}], actual error)
+}
+
+func TestCommandHandler_HandleMethodWithRetry(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		saverMock        *repositories.MockProjectionStore[*struct{}]
+		firstProvider    *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		secondProvider   *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected      = errors.New("test error")
+		expectedExecutor = &struct{}{}
+		expectedID       = uuid.New()
+		expectedCommand  = commands.Command[*struct{}]{
+			Events: []commands.CommandEvent[*struct{}]{{Type: 1, Payload: &struct{}{}}},
+		}
+		expectedConflict = coreRepositories.NewConcurrencyConflictError(expectedID, 1, 2)
+		expectedPolicy   = services.NewRetryPolicy(3, time.Millisecond, 2*time.Millisecond, 0)
+		expectBegin      = func(ctx context.Context) {
+			eventStoreMock.EXPECT().Begin(ctx).Return(expectedExecutor, nil).Once()
+		}
+		expectLoad = func(
+			ctx context.Context,
+			provider *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+		) {
+			expectBegin(ctx)
+			provider.EXPECT().ID().Return(expectedID)
+			eventStoreMock.EXPECT().
+				GetSnapshot(ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+				Return(0, nil, nil).
+				Once()
+			eventStoreMock.EXPECT().
+				GetEvents(ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+				Return(nil, nil).
+				Once()
+			provider.EXPECT().Build(func() []events.Event[*struct{}] { return nil }()).Return(nil)
+		}
+		expectHasCommand = func(ctx context.Context, handled bool) {
+			eventStoreMock.EXPECT().
+				HasCommand(ctx, "command-id", mock.Anything, expectedExecutor).
+				Return(handled, nil).
+				Once()
+		}
+		expectAttempt = func(
+			ctx context.Context,
+			provider *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+			updateErr error,
+		) {
+			expectLoad(ctx, provider)
+			provider.EXPECT().Version().Return(1)
+			provider.EXPECT().ApplyChanges(mock.Anything).Return(nil)
+			provider.EXPECT().Snapshot().Return(&struct{}{})
+			eventStoreMock.EXPECT().
+				UpdateOrCreateAggregate(ctx, mock.Anything, provider, mock.Anything, expectedExecutor).
+				Return(updateErr).
+				Once()
+		}
+	)
+	testCases := []CommandHandlerRetryTestCase{
+		{
+			description: "Если при вызове метода Handle возник конфликт версий, то команда должна быть повторно применена к заново загруженному агрегату", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			policy:      expectedPolicy,
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectAttempt(tc.ctx, firstProvider, expectedConflict)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+				expectAttempt(tc.ctx, secondProvider, nil)
+				secondProvider.EXPECT().Projection().Return(&struct{}{})
+				saverMock.EXPECT().Save(tc.ctx, mock.Anything).Return(nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				provider coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Same(t, secondProvider, provider)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle конфликт версий не устранен за отведенное число попыток, то должна вернуться ошибка конфликта", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			policy:      services.NewRetryPolicy(2, time.Millisecond, 0, time.Millisecond),
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectAttempt(tc.ctx, firstProvider, expectedConflict)
+				expectAttempt(tc.ctx, secondProvider, expectedConflict)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Twice()
+			},
+			dataAssertion: func(
+				_ coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.ErrorIs(t, actual, services.ErrConcurrencyConflict)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle с политикой повторов команда с тем же идентификатором уже была обработана, то возвращается пустой результат", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithID("command-id"),
+			policy:      expectedPolicy,
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectBegin(tc.ctx)
+				expectHasCommand(tc.ctx, true)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				provider coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Same(t, firstProvider, provider)
+			},
+		},
+		{
+			description: "Если после конфликта версий повторная попытка обнаружила уже обработанную команду, то возвращается пустой результат", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand.WithID("command-id"),
+			policy:      expectedPolicy,
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectHasCommand(tc.ctx, false)
+				expectAttempt(tc.ctx, firstProvider, expectedConflict)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+				expectBegin(tc.ctx)
+				expectHasCommand(tc.ctx, true)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				provider coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Same(t, secondProvider, provider)
+			},
+		},
+		{
+			description: "Если при вызове метода Handle возникла ошибка, не связанная с конфликтом версий, то повторная попытка не выполняется", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			policy:      expectedPolicy,
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectAttempt(tc.ctx, firstProvider, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				_ coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если во время ожидания повторной попытки контекст был отменен, то должна вернуться ошибка конфликта и ошибка контекста", //nolint:lll
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			}(),
+			cmd:    expectedCommand,
+			policy: services.NewRetryPolicy(2, time.Hour, 0, 0),
+			mockAssertion: func(tc CommandHandlerRetryTestCase) {
+				expectAttempt(tc.ctx, firstProvider, expectedConflict)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				_ coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				actual error,
+			) {
+				assert.ErrorIs(t, actual, services.ErrConcurrencyConflict)
+				assert.ErrorIs(t, actual, context.Canceled)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](
+					t,
+				)
+				saverMock = repositories.NewMockProjectionStore[*struct{}](t)
+				firstProvider = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				secondProvider = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				tc.mockAssertion(tc)
+
+				handler := services.NewCommandHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					saverMock,
+					services.WithRetry[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+						tc.policy,
+						func(id uuid.UUID) coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+							assert.Equal(t, expectedID, id)
+							return secondProvider
+						},
+					),
+				)
+				provider, err := handler.Execute(tc.ctx, tc.cmd, firstProvider)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(provider, err)
+				}
+			})
+	}
+}
+
+func TestTransactionalCommandHandler_HandleMethod(t *testing.T) {
+	var (
+		eventStoreMock        *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		saverMock             *repositories.MockTransactionalProjectionStore[*struct{}, *struct{}]
+		aggregateProviderMock *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected           = errors.New("test error")
+		expectedExecutor      = &struct{}{}
+		expectedID            = uuid.New()
+		expectedEvents        = []events.Event[*struct{}]{{AggregateID: expectedID}}
+		expectedCommand       = commands.Command[*struct{}]{
+			Events: []commands.CommandEvent[*struct{}]{{Type: 1, Payload: &struct{}{}}},
+		}
+		expectedSnapshot   = &struct{}{}
+		expectedProjection = &struct{}{}
+	)
+	changeAggregate := func(tc CommandHandlerTestCase) {
+		eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+		aggregateProviderMock.EXPECT().ID().Return(expectedID).Times(3)
+		eventStoreMock.EXPECT().
+			GetSnapshot(tc.ctx, expectedID, func() *int { return nil }(), expectedExecutor).
+			Return(0, nil, nil)
+		eventStoreMock.EXPECT().
+			GetEvents(tc.ctx, expectedID, 0, func() *int { return nil }(), expectedExecutor).
+			Return(expectedEvents, nil)
+		aggregateProviderMock.EXPECT().Build(expectedEvents).Return(nil)
+		aggregateProviderMock.EXPECT().Version().Return(0)
+		aggregateProviderMock.EXPECT().ApplyChanges(mock.Anything).Return(nil)
+		eventStoreMock.EXPECT().UpdateOrCreateAggregate(
+			tc.ctx,
+			mock.Anything,
+			aggregateProviderMock,
+			mock.Anything,
+			expectedExecutor,
+		).Return(nil)
+		aggregateProviderMock.EXPECT().Snapshot().Return(expectedSnapshot)
+		aggregateProviderMock.EXPECT().Projection().Return(expectedProjection)
+	}
+	testCases := []CommandHandlerTestCase{
+		{
+			description: "Если при вызове метода Handle не удалось сохранить проекцию в транзакции хранилища, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				changeAggregate(tc)
+				saverMock.EXPECT().Save(tc.ctx, expectedProjection, expectedExecutor).Return(errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "При вызове метода Handle проекция должна сохраняться в той же транзакции, что и события агрегата", //nolint:lll
+			ctx:         context.Background(),
+			cmd:         expectedCommand,
+			mockAssertion: func(tc CommandHandlerTestCase) {
+				changeAggregate(tc)
+				saverMock.EXPECT().Save(tc.ctx, expectedProjection, expectedExecutor).Return(nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](
+					t,
+				)
+				saverMock = repositories.NewMockTransactionalProjectionStore[*struct{}, *struct{}](t)
+				aggregateProviderMock = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				tc.mockAssertion(tc)
+
+				handler := services.NewTransactionalCommandHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					saverMock,
+				)
+				err := handler.Handle(tc.ctx, tc.cmd, aggregateProviderMock)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
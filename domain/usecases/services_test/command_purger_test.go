@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type CommandPurgerTestCase struct {
+	description   string
+	ctx           context.Context
+	mockAssertion func(tc CommandPurgerTestCase)
+	dataAssertion func(purged int64, actual error)
+}
+
+func TestCommandPurger_PurgeMethod(t *testing.T) {
+	var (
+		storeMock         *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		errExpected       = errors.New("test error")
+		expectedExecutor  = &struct{}{}
+		expectedRetention = time.Hour
+		expectedPurged    = int64(3)
+		beforeMatcher     = func(started time.Time) any {
+			return mock.MatchedBy(func(before time.Time) bool {
+				return !before.Before(started.Add(-expectedRetention)) &&
+					!before.After(time.Now().Add(-expectedRetention))
+			})
+		}
+	)
+	testCases := []CommandPurgerTestCase{
+		{
+			description: "Если при вызове метода Purge не удалось открыть транзакцию, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc CommandPurgerTestCase) {
+				storeMock.EXPECT().Begin(tc.ctx).Return(nil, errExpected)
+			},
+			dataAssertion: func(purged int64, actual error) {
+				assert.Zero(t, purged)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Purge не удалось очистить идентификаторы команд, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc CommandPurgerTestCase) {
+				storeMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				storeMock.EXPECT().
+					PurgeCommands(tc.ctx, beforeMatcher(time.Now()), expectedExecutor).
+					Return(0, errExpected)
+				storeMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(purged int64, actual error) {
+				assert.Zero(t, purged)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Purge не удалось зафиксировать транзакцию, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc CommandPurgerTestCase) {
+				storeMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				storeMock.EXPECT().
+					PurgeCommands(tc.ctx, beforeMatcher(time.Now()), expectedExecutor).
+					Return(expectedPurged, nil)
+				storeMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(errExpected)
+			},
+			dataAssertion: func(purged int64, actual error) {
+				assert.Zero(t, purged)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "При вызове метода Purge должны очищаться идентификаторы команд старше срока хранения",
+			ctx:         context.Background(),
+			mockAssertion: func(tc CommandPurgerTestCase) {
+				storeMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				storeMock.EXPECT().
+					PurgeCommands(tc.ctx, beforeMatcher(time.Now()), expectedExecutor).
+					Return(expectedPurged, nil)
+				storeMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(purged int64, actual error) {
+				assert.Equal(t, expectedPurged, purged)
+				assert.NoError(t, actual)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				storeMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				tc.mockAssertion(tc)
+
+				purger := services.NewCommandPurger[*struct{}, *struct{}, *struct{}](
+					storeMock,
+					services.WithPurgeRetention[*struct{}, *struct{}, *struct{}](expectedRetention),
+				)
+				tc.dataAssertion(purger.Purge(tc.ctx))
+			},
+		)
+	}
+}
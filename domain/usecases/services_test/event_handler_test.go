@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
 	"github.com/alex-fullstack/event-sourcingo/mocks/entities"
 	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
@@ -32,6 +33,16 @@ func TestEventHandler_HandleMethod(t *testing.T) {
 			{AggregateID: expectedID},
 		}
 		expectedIntegrationEvent = events.IntegrationEvent[*struct{}]{}
+		expectedMetadata         = events.NewMetadata(
+			"correlation-id",
+			"causation-id",
+			"actor",
+			map[string]string{"source": "test"},
+		)
+		expectedEventWithMetadata = events.Event[*struct{}]{
+			Metadata:    expectedMetadata,
+			AggregateID: expectedID,
+		}
 	)
 	testCases := []EventHandlerTestCase{
 		{
@@ -81,6 +92,23 @@ func TestEventHandler_HandleMethod(t *testing.T) {
 				assert.NoError(t, actual)
 			},
 		},
+		{
+			description: "При публикации интеграционных событий метод HandleEvents должен переносить в них метаданные событий", //nolint:lll
+			ctx:         context.Background(),
+			newEvents:   []events.Event[*struct{}]{expectedEventWithMetadata},
+			mockAssertion: func(tc EventHandlerTestCase) {
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEventWithMetadata).Return(nil)
+				aggregateProviderMock.EXPECT().
+					IntegrationEvent(0).
+					Return(expectedIntegrationEvent)
+				publisherMock.EXPECT().Publish(
+					tc.ctx, []events.IntegrationEvent[*struct{}]{{Metadata: expectedMetadata}}).
+					Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -93,13 +121,110 @@ func TestEventHandler_HandleMethod(t *testing.T) {
 				)
 				tc.mockAssertion(tc)
 
-				handler := services.NewEventHandler[*struct{}, *struct{}, *struct{}, *struct{}](
+				handler := services.NewEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
 					publisherMock,
 				)
 				err := handler.HandleEvents(
 					tc.ctx,
 					aggregateProviderMock,
 					tc.newEvents,
+					&struct{}{},
+				)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestOutboxEventHandler_HandleMethod(t *testing.T) {
+	var (
+		aggregateProviderMock *entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		outboxStoreMock       *repositories.MockOutboxStore[*struct{}, *struct{}]
+		errExpected           = errors.New("test error")
+		expectedExecutor      = &struct{}{}
+		expectedID            = uuid.New()
+		expectedEvents        = []events.Event[*struct{}]{
+			{AggregateID: expectedID},
+			{AggregateID: expectedID},
+		}
+		expectedIntegrationEvent = events.IntegrationEvent[*struct{}]{}
+		expectedMessages         = []*outbox.Message[*struct{}]{
+			outbox.NewMessage(expectedID, expectedIntegrationEvent),
+			outbox.NewMessage(expectedID, expectedIntegrationEvent),
+		}
+	)
+	testCases := []EventHandlerTestCase{
+		{
+			description: "Если при вызове метода HandleEvents не удалось применить событие, то интеграционные события не должны попадать в outbox", //nolint:lll
+			ctx:         context.Background(),
+			newEvents:   expectedEvents,
+			mockAssertion: func(_ EventHandlerTestCase) {
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEvents[0]).Return(errExpected)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода HandleEvents не удалось записать интеграционные события в outbox, то должна вернуться ошибка", //nolint:lll
+			ctx:         context.Background(),
+			newEvents:   expectedEvents,
+			mockAssertion: func(tc EventHandlerTestCase) {
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEvents[0]).Return(nil)
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEvents[1]).Return(nil)
+				aggregateProviderMock.EXPECT().
+					IntegrationEvent(0).
+					Return(expectedIntegrationEvent).
+					Twice()
+				outboxStoreMock.EXPECT().
+					Enqueue(tc.ctx, expectedMessages, expectedExecutor).
+					Return(errExpected)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "При вызове метода HandleEvents интеграционные события должны записываться в outbox в рамках переданной транзакции", //nolint:lll
+			ctx:         context.Background(),
+			newEvents:   expectedEvents,
+			mockAssertion: func(tc EventHandlerTestCase) {
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEvents[0]).Return(nil)
+				aggregateProviderMock.EXPECT().ApplyChange(expectedEvents[1]).Return(nil)
+				aggregateProviderMock.EXPECT().
+					IntegrationEvent(0).
+					Return(expectedIntegrationEvent).
+					Twice()
+				outboxStoreMock.EXPECT().
+					Enqueue(tc.ctx, expectedMessages, expectedExecutor).
+					Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				outboxStoreMock = repositories.NewMockOutboxStore[*struct{}, *struct{}](t)
+				aggregateProviderMock = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				tc.mockAssertion(tc)
+
+				handler := services.NewOutboxEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					outboxStoreMock,
+				)
+				err := handler.HandleEvents(
+					tc.ctx,
+					aggregateProviderMock,
+					tc.newEvents,
+					expectedExecutor,
 				)
 
 				if tc.dataAssertion != nil {
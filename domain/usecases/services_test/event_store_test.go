@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories/eventstoretest"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/memory"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/postgresql"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/sqlite"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+const postgresDSNEnv = "EVENTSTORE_POSTGRES_DSN"
+
+func TestMemoryEventStore_Conformance(t *testing.T) {
+	eventstoretest.Run(
+		t,
+		func(*testing.T) repositories.EventStore[eventstoretest.Payload, eventstoretest.Snapshot, *memory.Transaction] {
+			return memory.NewEventStore[eventstoretest.Payload, eventstoretest.Snapshot]()
+		},
+	)
+}
+
+func TestSQLiteEventStore_Conformance(t *testing.T) {
+	eventstoretest.Run(
+		t,
+		func(t *testing.T) repositories.EventStore[eventstoretest.Payload, eventstoretest.Snapshot, *sql.Tx] {
+			store, err := sqlite.NewSQLiteDB[eventstoretest.Payload, eventstoretest.Snapshot](
+				context.Background(),
+				"file:"+filepath.Join(t.TempDir(), "events.db"),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, store.Close())
+			})
+			return store
+		},
+	)
+}
+
+func TestPostgresEventStore_Conformance(t *testing.T) {
+	store := newPostgresStore(t)
+
+	eventstoretest.Run(
+		t,
+		func(t *testing.T) repositories.EventStore[
+			eventstoretest.Payload,
+			eventstoretest.Snapshot,
+			postgresql.Transaction,
+		] {
+			tables := store.Options().Tables
+			truncatePostgres(
+				t,
+				store,
+				tables.Aggregates,
+				tables.Events,
+				tables.Transactions,
+				tables.Snapshots,
+				tables.Subscriptions,
+			)
+			return store
+		},
+	)
+}
+
+func newPostgresStore(t *testing.T) *postgresql.PostgresDB[eventstoretest.Payload, eventstoretest.Snapshot] {
+	t.Helper()
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	store, err := postgresql.NewPostgresDB[eventstoretest.Payload, eventstoretest.Snapshot](
+		context.Background(),
+		cfg,
+	)
+	require.NoError(t, err)
+	t.Cleanup(store.Close)
+	require.NoError(t, postgresql.Migrate(
+		context.Background(),
+		store.Pool(),
+		store.Options(),
+		postgresql.WithPublication(),
+	))
+	return store
+}
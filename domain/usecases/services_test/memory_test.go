@@ -0,0 +1,257 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/commands"
+	coreEntities "github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counterProjection struct {
+	ID      uuid.UUID
+	Version int
+	Value   int
+}
+
+type counter struct {
+	*coreEntities.Aggregate[int, int]
+	value int
+}
+
+func newCounter(id uuid.UUID) coreEntities.AggregateProvider[int, int, *counterProjection, int] {
+	c := &counter{}
+	c.Aggregate = coreEntities.NewAggregate[int, int](
+		id,
+		2,
+		func(event events.Event[int]) error {
+			c.value += event.Payload
+			return nil
+		},
+		func(payload int) error {
+			c.value = payload
+			return nil
+		},
+	)
+	return c
+}
+
+func (c *counter) Snapshot() int {
+	return c.value
+}
+
+func (c *counter) Projection() *counterProjection {
+	return &counterProjection{ID: c.ID(), Version: c.Version(), Value: c.value}
+}
+
+func (c *counter) IntegrationEvent(evType int) events.IntegrationEvent[int] {
+	return events.NewIntegrationEvent(c.ID(), evType, c.value)
+}
+
+func TestMemoryStores_EndToEnd(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewEventStore[int, int]()
+	projections := memory.NewProjectionStore(func(p *counterProjection) (uuid.UUID, int) {
+		return p.ID, p.Version
+	})
+	publisher := memory.NewPublisher[int]()
+	commandHandler := services.NewTransactionalCommandHandler[int, int, *counterProjection, int, *memory.Transaction](
+		store,
+		projections.Transactional(),
+	)
+	transactionHandler := services.NewTransactionHandler[int, int, *counterProjection, int, *memory.Transaction](
+		store,
+		services.NewEventHandler[int, int, *counterProjection, int, *memory.Transaction](publisher),
+		"test-subscription",
+		slog.Default(),
+	)
+	id := uuid.New()
+	add := func(values ...int) commands.Command[int] {
+		commandEvents := make([]commands.CommandEvent[int], 0, len(values))
+		for _, value := range values {
+			commandEvents = append(commandEvents, commands.NewCommandEvent(1, value))
+		}
+		return commands.NewCommand(1, commandEvents)
+	}
+
+	t.Run("Команды должны записывать события и проекцию агрегата в одной транзакции", func(t *testing.T) {
+		require.NoError(t, commandHandler.Handle(ctx, add(1, 2).WithNoStream().WithID("first"), newCounter(id)))
+		require.NoError(t, commandHandler.Handle(ctx, add(3).WithExpectedVersion(2), newCounter(id)))
+
+		projection, err := projections.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, &counterProjection{ID: id, Version: 3, Value: 6}, projection)
+	})
+
+	t.Run("Повторная команда с тем же идентификатором не должна записывать новые события", func(t *testing.T) {
+		require.NoError(t, commandHandler.Handle(ctx, add(10).WithID("first"), newCounter(id)))
+
+		projection, err := projections.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 6, projection.Value)
+	})
+
+	t.Run("Отклоненная команда не должна оставлять изменений после отката транзакции", func(t *testing.T) {
+		err := commandHandler.Handle(ctx, add(10).WithExpectedVersion(1), newCounter(id))
+		require.ErrorIs(t, err, commands.ErrWrongExpectedVersion)
+
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		history, err := store.GetEvents(ctx, id, 0, nil, tx)
+		require.NoError(t, err)
+		require.NoError(t, store.Rollback(ctx, tx))
+		assert.Len(t, history, 3)
+	})
+
+	t.Run("Обработчик транзакций должен публиковать интеграционные события и сдвигать подписку", func(t *testing.T) {
+		require.NoError(t, transactionHandler.CatchUp(ctx, newCounter))
+
+		published := publisher.Published()
+		require.Len(t, published, 3)
+		assert.Equal(t, []int{1, 3, 6}, []int{published[0].Payload, published[1].Payload, published[2].Payload})
+
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		sub, err := store.GetSubscription(ctx, "test-subscription", tx)
+		require.NoError(t, err)
+		require.NoError(t, store.Rollback(ctx, tx))
+		assert.Equal(t, int64(2), sub.LastSequenceID)
+	})
+}
+
+func TestMemoryEventStore_Isolation(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewEventStore[int, int]()
+	write := func(tx *memory.Transaction, id uuid.UUID, baseVersion int, values ...int) error {
+		aggregate := newCounter(id)
+		if baseVersion > 0 {
+			history := make([]events.Event[int], 0, baseVersion)
+			for version := 1; version <= baseVersion; version++ {
+				history = append(history, events.NewEvent(id, uuid.Nil, 1, version, 1, 0))
+			}
+			require.NoError(t, aggregate.Build(history))
+		}
+		transaction := transactions.NewTransaction(uuid.New(), id, 0)
+		changes := make([]events.Event[int], 0, len(values))
+		for i, value := range values {
+			changes = append(changes, events.NewEvent(id, transaction.ID, 1, baseVersion+i+1, 1, value))
+		}
+		require.NoError(t, aggregate.ApplyChanges(changes))
+		return store.UpdateOrCreateAggregate(ctx, transaction, aggregate, 0, tx)
+	}
+	history := func(id uuid.UUID) []events.Event[int] {
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, store.Rollback(ctx, tx))
+		}()
+		result, err := store.GetEvents(ctx, id, 0, nil, tx)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Открытая читающая транзакция не должна блокировать запись и должна видеть свой снимок", func(t *testing.T) {
+		id := uuid.New()
+		reader, err := store.Begin(ctx)
+		require.NoError(t, err)
+		writer, err := store.Begin(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, write(writer, id, 0, 1))
+		require.NoError(t, store.Commit(ctx, writer))
+
+		seen, err := store.GetEvents(ctx, id, 0, nil, reader)
+		require.NoError(t, err)
+		assert.Empty(t, seen)
+		require.NoError(t, store.Commit(ctx, reader))
+		assert.Len(t, history(id), 1)
+	})
+
+	t.Run("Параллельные транзакции по разным агрегатам должны фиксироваться обе", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		firstTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		secondTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, write(firstTx, first, 0, 1))
+		require.NoError(t, write(secondTx, second, 0, 2))
+		require.NoError(t, store.Commit(ctx, firstTx))
+		require.NoError(t, store.Commit(ctx, secondTx))
+
+		assert.Len(t, history(first), 1)
+		assert.Len(t, history(second), 1)
+	})
+
+	t.Run("Параллельная запись в один агрегат должна завершаться конфликтом при фиксации", func(t *testing.T) {
+		id := uuid.New()
+		firstTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		secondTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, write(firstTx, id, 0, 1))
+		require.NoError(t, write(secondTx, id, 0, 2))
+		require.NoError(t, store.Commit(ctx, firstTx))
+		require.ErrorIs(t, store.Commit(ctx, secondTx), repositories.ErrConcurrencyConflict)
+
+		stored := history(id)
+		require.Len(t, stored, 1)
+		assert.Equal(t, 1, stored[0].Payload)
+	})
+
+	t.Run("Параллельный сдвиг одной подписки должен завершаться конфликтом при фиксации", func(t *testing.T) {
+		firstTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		secondTx, err := store.Begin(ctx)
+		require.NoError(t, err)
+
+		for i, tx := range []*memory.Transaction{firstTx, secondTx} {
+			sub, errGet := store.GetSubscription(ctx, "isolation", tx)
+			require.NoError(t, errGet)
+			sub.LastSequenceID = int64(i + 1)
+			require.NoError(t, store.UpdateSubscription(ctx, sub, tx))
+		}
+		require.NoError(t, store.Commit(ctx, firstTx))
+		require.ErrorIs(t, store.Commit(ctx, secondTx), repositories.ErrConcurrencyConflict)
+	})
+
+	t.Run("Конкурентные транзакции не должны блокировать друг друга", func(t *testing.T) {
+		const writers = 8
+		ids := make([]uuid.UUID, writers)
+		var wg sync.WaitGroup
+		for i := range ids {
+			ids[i] = uuid.New()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					tx, err := store.Begin(ctx)
+					if !assert.NoError(t, err) {
+						return
+					}
+					if err = write(tx, ids[i], 0, i); !assert.NoError(t, err) {
+						return
+					}
+					if err = store.Commit(ctx, tx); err == nil {
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		for _, id := range ids {
+			assert.Len(t, history(id), 1)
+		}
+	})
+}
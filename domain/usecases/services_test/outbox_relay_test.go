@@ -0,0 +1,229 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type OutboxRelayTestCase struct {
+	description   string
+	ctx           context.Context
+	mockAssertion func(tc OutboxRelayTestCase)
+	dataAssertion func(relayed int, actual error)
+}
+
+func newOutboxMessage(id int64, aggregateID uuid.UUID, evType int) *outbox.Message[*struct{}] {
+	message := outbox.NewMessage(aggregateID, events.IntegrationEvent[*struct{}]{Type: evType})
+	message.ID = id
+	return message
+}
+
+func TestOutboxRelay_RelayMethod(t *testing.T) {
+	var (
+		committerMock       *repositories.MockTFACommitter[*struct{}]
+		outboxStoreMock     *repositories.MockOutboxStore[*struct{}, *struct{}]
+		publisherMock       *repositories.MockPublisher[*struct{}]
+		errExpected         = errors.New("test error")
+		errRollback         = errors.New("rollback error")
+		expectedExecutor    = &struct{}{}
+		expectedBatchSize   = 10
+		expectedMaxAttempts = 3
+		firstAggregateID    = uuid.New()
+		secondAggregateID   = uuid.New()
+		expectedMessages    = []*outbox.Message[*struct{}]{
+			newOutboxMessage(1, firstAggregateID, 1),
+			newOutboxMessage(2, secondAggregateID, 2),
+			newOutboxMessage(3, firstAggregateID, 3),
+		}
+		firstAggregateEvents = []events.IntegrationEvent[*struct{}]{
+			expectedMessages[0].Event,
+			expectedMessages[2].Event,
+		}
+		secondAggregateEvents = []events.IntegrationEvent[*struct{}]{
+			expectedMessages[1].Event,
+		}
+	)
+	testCases := []OutboxRelayTestCase{
+		{
+			description: "Если при вызове метода Relay не удалось открыть транзакцию, то должна вернуться ошибка",
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(nil, errExpected)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 0, relayed)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Relay не удалось получить сообщения outbox, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return(nil, errExpected)
+				committerMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 0, relayed)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "При вызове метода Relay сообщения должны публиковаться по агрегатам в порядке их записи и помечаться отправленными", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return(expectedMessages, nil)
+				publisherMock.EXPECT().Publish(tc.ctx, firstAggregateEvents).Return(nil)
+				publisherMock.EXPECT().Publish(tc.ctx, secondAggregateEvents).Return(nil)
+				outboxStoreMock.EXPECT().
+					MarkSent(tc.ctx, []int64{1, 3, 2}, expectedExecutor).
+					Return(nil)
+				committerMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, len(expectedMessages), relayed)
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода Relay не удалось опубликовать сообщения агрегата, то они должны помечаться неотправленными, а остальные агрегаты публиковаться", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return(expectedMessages, nil)
+				publisherMock.EXPECT().Publish(tc.ctx, firstAggregateEvents).Return(errExpected)
+				outboxStoreMock.EXPECT().
+					MarkFailed(tc.ctx, []int64{1, 3}, errExpected.Error(), expectedExecutor).
+					Return(nil)
+				publisherMock.EXPECT().Publish(tc.ctx, secondAggregateEvents).Return(nil)
+				outboxStoreMock.EXPECT().
+					MarkSent(tc.ctx, []int64{2}, expectedExecutor).
+					Return(nil)
+				committerMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, len(expectedMessages), relayed)
+				assert.ErrorIs(t, actual, errExpected)
+			},
+		},
+		{
+			description: "Если одно из сообщений агрегата исчерпало попытки публикации, то должны откладываться все сообщения агрегата", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				exhausted := newOutboxMessage(1, firstAggregateID, 1)
+				exhausted.Attempts = expectedMaxAttempts - 1
+				fresh := newOutboxMessage(3, firstAggregateID, 3)
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return([]*outbox.Message[*struct{}]{exhausted, fresh}, nil)
+				publisherMock.EXPECT().Publish(tc.ctx, firstAggregateEvents).Return(errExpected)
+				outboxStoreMock.EXPECT().
+					Park(tc.ctx, []int64{1, 3}, errExpected.Error(), expectedExecutor).
+					Return(nil)
+				committerMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 2, relayed)
+				assert.ErrorIs(t, actual, errExpected)
+			},
+		},
+		{
+			description: "Если не удалось отложить сообщения, исчерпавшие попытки публикации, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				exhausted := newOutboxMessage(2, secondAggregateID, 2)
+				exhausted.Attempts = expectedMaxAttempts
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return([]*outbox.Message[*struct{}]{exhausted}, nil)
+				publisherMock.EXPECT().Publish(tc.ctx, secondAggregateEvents).Return(errExpected)
+				outboxStoreMock.EXPECT().
+					Park(tc.ctx, []int64{2}, errExpected.Error(), expectedExecutor).
+					Return(errExpected)
+				committerMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 0, relayed)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если не удалось откатить транзакцию, то должны вернуться и исходная ошибка, и ошибка отката",
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return(nil, errExpected)
+				committerMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(errRollback)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 0, relayed)
+				assert.ErrorIs(t, actual, errExpected)
+				assert.ErrorIs(t, actual, errRollback)
+			},
+		},
+		{
+			description: "Если при вызове метода Relay не удалось пометить сообщения отправленными, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc OutboxRelayTestCase) {
+				committerMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				outboxStoreMock.EXPECT().
+					GetPending(tc.ctx, expectedBatchSize, expectedExecutor).
+					Return(expectedMessages[1:2], nil)
+				publisherMock.EXPECT().Publish(tc.ctx, secondAggregateEvents).Return(nil)
+				outboxStoreMock.EXPECT().
+					MarkSent(tc.ctx, []int64{2}, expectedExecutor).
+					Return(errExpected)
+				committerMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(relayed int, actual error) {
+				assert.Equal(t, 0, relayed)
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				committerMock = repositories.NewMockTFACommitter[*struct{}](t)
+				outboxStoreMock = repositories.NewMockOutboxStore[*struct{}, *struct{}](t)
+				publisherMock = repositories.NewMockPublisher[*struct{}](t)
+				tc.mockAssertion(tc)
+
+				relay := services.NewOutboxRelay[*struct{}, *struct{}](
+					committerMock,
+					outboxStoreMock,
+					publisherMock,
+					slog.Default(),
+					services.WithOutboxBatchSize[*struct{}, *struct{}](expectedBatchSize),
+					services.WithOutboxMaxAttempts[*struct{}, *struct{}](expectedMaxAttempts),
+				)
+				relayed, err := relay.Relay(tc.ctx)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(relayed, err)
+				}
+			})
+	}
+}
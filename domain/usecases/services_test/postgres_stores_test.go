@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories/eventstoretest"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/postgresql"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roleProjection struct {
+	ID      uuid.UUID `json:"id"`
+	Version int       `json:"version"`
+	Name    string    `json:"name"`
+}
+
+func TestPostgresProjectionStore(t *testing.T) {
+	ctx := context.Background()
+	store := newPostgresStore(t)
+	truncatePostgres(t, store, store.Options().Tables.Projections)
+	projections := postgresql.NewProjectionStore[roleProjection](
+		store.Pool(),
+		"roles",
+		func(projection roleProjection) (uuid.UUID, int) {
+			return projection.ID, projection.Version
+		},
+		postgresql.WithProjectionStoreOptions(store.Options()),
+		postgresql.WithProjectionIndex("name"),
+		postgresql.WithProjectionGINIndex(),
+	)
+	admin := roleProjection{ID: uuid.New(), Version: 2, Name: "admin"}
+	user := roleProjection{ID: uuid.New(), Version: 1, Name: "user"}
+
+	t.Run("Индексы проекций должны создаваться повторно без ошибок", func(t *testing.T) {
+		require.NoError(t, projections.EnsureIndexes(ctx))
+		require.NoError(t, projections.EnsureIndexes(ctx))
+	})
+	t.Run("Сохраненная проекция должна читаться по идентификатору, а устаревшая версия не должна ее перезаписывать", func(t *testing.T) { //nolint:lll
+		require.NoError(t, projections.Save(ctx, admin))
+		require.NoError(t, projections.Save(ctx, roleProjection{ID: admin.ID, Version: 1, Name: "stale"}))
+
+		actual, err := projections.Get(ctx, admin.ID)
+		require.NoError(t, err)
+		assert.Equal(t, admin, actual)
+	})
+	t.Run("Проекция, сохраненная в транзакции хранилища, должна появляться только после фиксации", func(t *testing.T) { //nolint:lll
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		require.NoError(t, projections.Transactional().Save(ctx, user, tx))
+		_, err = projections.Get(ctx, user.ID)
+		assert.ErrorIs(t, err, repositories.ErrProjectionNotFound)
+		require.NoError(t, store.Commit(ctx, tx))
+
+		actual, err := projections.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user, actual)
+	})
+	t.Run("Проекции должны возвращаться в порядке запрошенных идентификаторов и находиться по фильтру", func(t *testing.T) { //nolint:lll
+		many, err := projections.GetMany(ctx, []uuid.UUID{user.ID, admin.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []roleProjection{user, admin}, many)
+
+		found, err := projections.Find(ctx, postgresql.ProjectionQuery{
+			Filters: []postgresql.ProjectionFilter{
+				postgresql.NewProjectionFilter("name", postgresql.FilterEq, "admin"),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []roleProjection{admin}, found)
+	})
+	t.Run("Удаленная проекция не должна находиться", func(t *testing.T) {
+		require.NoError(t, projections.Delete(ctx, admin.ID))
+
+		_, err := projections.Get(ctx, admin.ID)
+		assert.ErrorIs(t, err, repositories.ErrProjectionNotFound)
+	})
+}
+
+func TestPostgresOutbox(t *testing.T) {
+	ctx := context.Background()
+	store := newPostgresStore(t)
+	truncatePostgres(t, store, store.Options().Tables.Outbox)
+	box := postgresql.NewOutbox[string](store.Options())
+	firstAggregateID, secondAggregateID := uuid.New(), uuid.New()
+
+	inTx := func(t *testing.T, fn func(tx postgresql.Transaction)) {
+		t.Helper()
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		fn(tx)
+		require.NoError(t, store.Commit(ctx, tx))
+	}
+	pending := func(t *testing.T) []*outbox.Message[string] {
+		t.Helper()
+		var messages []*outbox.Message[string]
+		inTx(t, func(tx postgresql.Transaction) {
+			var err error
+			messages, err = box.GetPending(ctx, 10, tx)
+			require.NoError(t, err)
+		})
+		return messages
+	}
+	inTx(t, func(tx postgresql.Transaction) {
+		require.NoError(t, box.Enqueue(ctx, []*outbox.Message[string]{
+			outbox.NewMessage(firstAggregateID, events.NewIntegrationEvent(uuid.New(), 1, "first")),
+			outbox.NewMessage(secondAggregateID, events.NewIntegrationEvent(uuid.New(), 1, "second")),
+			outbox.NewMessage(firstAggregateID, events.NewIntegrationEvent(uuid.New(), 2, "third")),
+		}, tx))
+	})
+
+	messages := pending(t)
+	require.Len(t, messages, 3)
+
+	t.Run("Неотправленные сообщения должны выбираться по порядку с учетом неудачных попыток", func(t *testing.T) {
+		assert.Equal(t, "first", messages[0].Event.Payload)
+		assert.Equal(t, "second", messages[1].Event.Payload)
+		assert.Equal(t, "third", messages[2].Event.Payload)
+		inTx(t, func(tx postgresql.Transaction) {
+			require.NoError(t, box.MarkFailed(ctx, []int64{messages[1].ID}, "test error", tx))
+		})
+
+		actual := pending(t)
+		require.Len(t, actual, 3)
+		assert.Equal(t, 1, actual[1].Attempts)
+	})
+	t.Run("Отложенное сообщение агрегата должно задерживать все его последующие сообщения", func(t *testing.T) {
+		inTx(t, func(tx postgresql.Transaction) {
+			require.NoError(t, box.Park(ctx, []int64{messages[0].ID}, "test error", tx))
+		})
+
+		actual := pending(t)
+		require.Len(t, actual, 1)
+		assert.Equal(t, messages[1].ID, actual[0].ID)
+	})
+	t.Run("Отправленные сообщения не должны выбираться повторно", func(t *testing.T) {
+		inTx(t, func(tx postgresql.Transaction) {
+			require.NoError(t, box.MarkSent(ctx, []int64{messages[1].ID}, tx))
+		})
+
+		assert.Empty(t, pending(t))
+	})
+}
+
+func truncatePostgres(
+	t *testing.T,
+	store *postgresql.PostgresDB[eventstoretest.Payload, eventstoretest.Snapshot],
+	tables ...string,
+) {
+	t.Helper()
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, pgx.Identifier{store.Options().Schema, table}.Sanitize())
+	}
+	_, err := store.Pool().Exec(context.Background(), `TRUNCATE `+strings.Join(names, ", "))
+	require.NoError(t, err)
+}
@@ -0,0 +1,274 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	coreEntities "github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/mocks/entities"
+	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProjectionRebuilderTestCase struct {
+	description   string
+	ctx           context.Context
+	opts          services.RebuildOptions
+	registryOnly  bool
+	mockAssertion func(tc ProjectionRebuilderTestCase)
+	dataAssertion func(progress services.RebuildProgress, reported []services.RebuildProgress, actual error)
+}
+
+func TestProjectionRebuilder_RebuildMethod(t *testing.T) {
+	var (
+		eventStoreMock   *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		saverMock        *repositories.MockProjectionStore[*struct{}]
+		providerMocks    map[uuid.UUID]*entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected      = errors.New("test error")
+		expectedExecutor = &struct{}{}
+		expectedIDs      = slices.SortedFunc(slices.Values([]uuid.UUID{uuid.New(), uuid.New(), uuid.New()}),
+			func(a, b uuid.UUID) int {
+				return slices.Compare(a[:], b[:])
+			})
+		expectedProjection    = &struct{}{}
+		expectedAggregateType = "user"
+		registered            []uuid.UUID
+	)
+	expectRebuild := func(id uuid.UUID, saveErr error) {
+		history := []events.Event[*struct{}]{{AggregateID: id}}
+		provider := providerMocks[id]
+		eventStoreMock.EXPECT().Begin(mock.Anything).Return(expectedExecutor, nil).Once()
+		provider.EXPECT().ID().Return(id).Times(2)
+		eventStoreMock.EXPECT().
+			GetSnapshot(mock.Anything, id, func() *int { return nil }(), expectedExecutor).
+			Return(0, nil, nil)
+		eventStoreMock.EXPECT().
+			GetEvents(mock.Anything, id, 0, func() *int { return nil }(), expectedExecutor).
+			Return(history, nil)
+		provider.EXPECT().Build(history).Return(nil)
+		eventStoreMock.EXPECT().Rollback(mock.Anything, expectedExecutor).Return(nil).Once()
+		provider.EXPECT().Projection().Return(expectedProjection)
+		saverMock.EXPECT().Save(mock.Anything, expectedProjection).Return(saveErr).Once()
+	}
+	expectTypes := func(ctx context.Context, page []uuid.UUID, types map[uuid.UUID]string) {
+		eventStoreMock.EXPECT().Begin(ctx).Return(expectedExecutor, nil).Once()
+		eventStoreMock.EXPECT().GetAggregateTypes(ctx, page, expectedExecutor).Return(types, nil).Once()
+		eventStoreMock.EXPECT().Rollback(ctx, expectedExecutor).Return(nil).Once()
+	}
+	testCases := []ProjectionRebuilderTestCase{
+		{
+			description: "При вызове метода Rebuild без списка идентификаторов проекции всех агрегатов должны пересобираться постранично провайдерами их собственных типов", //nolint:lll
+			ctx:         context.Background(),
+			opts:        services.RebuildOptions{Workers: 1, PageSize: 2},
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Once()
+				eventStoreMock.EXPECT().
+					GetAggregateIDs(tc.ctx, uuid.Nil, 2, "", expectedExecutor).
+					Return(expectedIDs[:2], nil)
+				eventStoreMock.EXPECT().
+					GetAggregateTypes(tc.ctx, expectedIDs[:2], expectedExecutor).
+					Return(map[uuid.UUID]string{expectedIDs[0]: expectedAggregateType, expectedIDs[1]: ""}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+				expectRebuild(expectedIDs[0], nil)
+				expectRebuild(expectedIDs[1], nil)
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Once()
+				eventStoreMock.EXPECT().
+					GetAggregateIDs(tc.ctx, expectedIDs[1], 2, "", expectedExecutor).
+					Return([]uuid.UUID{}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Equal(t, services.RebuildProgress{Processed: 2, Cursor: expectedIDs[1]}, progress)
+				assert.Equal(t, []services.RebuildProgress{progress}, reported)
+				assert.Equal(t, []uuid.UUID{expectedIDs[0]}, registered)
+			},
+		},
+		{
+			description: "При вызове метода Rebuild с типом агрегата должны выбираться только агрегаты этого типа, а провайдеры создаваться через реестр", //nolint:lll
+			ctx:         context.Background(),
+			opts:        services.RebuildOptions{AggregateType: expectedAggregateType, Workers: 1, PageSize: 2},
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Once()
+				eventStoreMock.EXPECT().
+					GetAggregateIDs(tc.ctx, uuid.Nil, 2, expectedAggregateType, expectedExecutor).
+					Return(expectedIDs[2:], nil)
+				eventStoreMock.EXPECT().
+					GetAggregateTypes(tc.ctx, expectedIDs[2:], expectedExecutor).
+					Return(map[uuid.UUID]string{expectedIDs[2]: expectedAggregateType}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+				expectRebuild(expectedIDs[2], nil)
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Once()
+				eventStoreMock.EXPECT().
+					GetAggregateIDs(tc.ctx, expectedIDs[2], 2, expectedAggregateType, expectedExecutor).
+					Return([]uuid.UUID{}, nil)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil).Once()
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Equal(t, services.RebuildProgress{Processed: 1, Cursor: expectedIDs[2]}, progress)
+				assert.Equal(t, []services.RebuildProgress{progress}, reported)
+				assert.Equal(t, []uuid.UUID{expectedIDs[2]}, registered)
+			},
+		},
+		{
+			description: "Если тип агрегата не зарегистрирован в реестре, то метод Rebuild должен вернуть ошибку",
+			ctx:         context.Background(),
+			opts: services.RebuildOptions{
+				IDs:      expectedIDs[:1],
+				Workers:  1,
+				PageSize: 1,
+			},
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				expectTypes(tc.ctx, expectedIDs[:1], map[uuid.UUID]string{expectedIDs[0]: "unknown"})
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.ErrorIs(t, actual, coreEntities.ErrUnknownAggregateType)
+				assert.Equal(t, services.RebuildProgress{}, progress)
+				assert.Empty(t, reported)
+			},
+		},
+		{
+			description: "Если задан только реестр без фабрики для пустого типа, а тип агрегата не сохранен, то метод Rebuild должен вернуть ошибку вместо паники", //nolint:lll
+			ctx:         context.Background(),
+			opts: services.RebuildOptions{
+				IDs:      expectedIDs[:1],
+				Workers:  1,
+				PageSize: 1,
+			},
+			registryOnly: true,
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				expectTypes(tc.ctx, expectedIDs[:1], map[uuid.UUID]string{})
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.ErrorIs(t, actual, coreEntities.ErrUnknownAggregateType)
+				assert.Equal(t, services.RebuildProgress{}, progress)
+				assert.Empty(t, reported)
+			},
+		},
+		{
+			description: "При вызове метода Rebuild со списком идентификаторов и курсором должны пересобираться только агрегаты после курсора", //nolint:lll
+			ctx:         context.Background(),
+			opts: services.RebuildOptions{
+				IDs:      []uuid.UUID{expectedIDs[2], expectedIDs[0], expectedIDs[1]},
+				After:    expectedIDs[0],
+				Workers:  1,
+				PageSize: 1,
+			},
+			registryOnly: true,
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				expectTypes(tc.ctx, expectedIDs[1:2], map[uuid.UUID]string{expectedIDs[1]: expectedAggregateType})
+				expectRebuild(expectedIDs[1], nil)
+				expectTypes(tc.ctx, expectedIDs[2:], map[uuid.UUID]string{expectedIDs[2]: expectedAggregateType})
+				expectRebuild(expectedIDs[2], nil)
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.NoError(t, actual)
+				assert.Equal(t, services.RebuildProgress{Processed: 2, Cursor: expectedIDs[2]}, progress)
+				assert.Equal(t, []services.RebuildProgress{
+					{Processed: 1, Cursor: expectedIDs[1]},
+					{Processed: 2, Cursor: expectedIDs[2]},
+				}, reported)
+				assert.Equal(t, expectedIDs[1:], registered)
+			},
+		},
+		{
+			description: "Если при вызове метода Rebuild не удалось сохранить проекцию, то должна вернуться ошибка с курсором последней завершенной страницы", //nolint:lll
+			ctx:         context.Background(),
+			opts: services.RebuildOptions{
+				IDs:      expectedIDs[:2],
+				Workers:  1,
+				PageSize: 1,
+			},
+			mockAssertion: func(tc ProjectionRebuilderTestCase) {
+				expectTypes(tc.ctx, expectedIDs[:1], map[uuid.UUID]string{expectedIDs[0]: expectedAggregateType})
+				expectRebuild(expectedIDs[0], nil)
+				expectTypes(tc.ctx, expectedIDs[1:2], map[uuid.UUID]string{expectedIDs[1]: expectedAggregateType})
+				expectRebuild(expectedIDs[1], errExpected)
+			},
+			dataAssertion: func(
+				progress services.RebuildProgress,
+				reported []services.RebuildProgress,
+				actual error,
+			) {
+				assert.Equal(t, errExpected, actual)
+				assert.Equal(t, services.RebuildProgress{Processed: 1, Cursor: expectedIDs[0]}, progress)
+				assert.Len(t, reported, 1)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				saverMock = repositories.NewMockProjectionStore[*struct{}](t)
+				providerMocks = make(
+					map[uuid.UUID]*entities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+				)
+				for _, id := range expectedIDs {
+					providerMocks[id] = entities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](t)
+				}
+				registered = nil
+				registry := coreEntities.NewAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}]()
+				assert.NoError(t, registry.Register(
+					expectedAggregateType,
+					func(id uuid.UUID) coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+						registered = append(registered, id)
+						return providerMocks[id]
+					},
+				))
+				tc.mockAssertion(tc)
+
+				var reported []services.RebuildProgress
+				opts := tc.opts
+				opts.Progress = func(progress services.RebuildProgress) {
+					reported = append(reported, progress)
+				}
+				providerFn := func(id uuid.UUID) coreEntities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+					return providerMocks[id]
+				}
+				if tc.registryOnly {
+					providerFn = nil
+				}
+				rebuilder := services.NewProjectionRebuilder[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					saverMock,
+					providerFn,
+					services.WithRebuildAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](registry),
+				)
+				progress, err := rebuilder.Rebuild(tc.ctx, opts)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(progress, reported, err)
+				}
+			})
+	}
+}
@@ -0,0 +1,237 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/projections"
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	coreRepositories "github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type ProjectorTestCase struct {
+	description   string
+	ctx           context.Context
+	projections   []*projections.Projection[*struct{}, *struct{}]
+	handled       map[string][]int
+	mockAssertion func(tc ProjectorTestCase)
+	dataAssertion func(actual error)
+}
+
+func TestProjector_ProjectMethod(t *testing.T) {
+	var (
+		eventStoreMock    *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		handled           map[string][]int
+		errExpected       = errors.New("test error")
+		errRollback       = errors.New("rollback error")
+		expectedExecutor  = &struct{}{}
+		expectedBatchSize = 2
+		firstAggregateID  = uuid.New()
+		secondAggregateID = uuid.New()
+		expectedBatch     = []*transactions.Transaction{
+			transactions.NewTransaction(uuid.New(), firstAggregateID, 25),
+			transactions.NewTransaction(uuid.New(), secondAggregateID, 27),
+		}
+		firstEvents = []events.Event[*struct{}]{
+			{AggregateID: firstAggregateID, Type: 1},
+			{AggregateID: firstAggregateID, Type: 2},
+		}
+		secondEvents                 = []events.Event[*struct{}]{{AggregateID: secondAggregateID, Type: 1}}
+		expectedLastSequenceID int64 = 24
+		secondLastSequenceID   int64 = 20
+		mu                     sync.Mutex
+	)
+	newProjection := func(name string, err error) *projections.Projection[*struct{}, *struct{}] {
+		return projections.NewProjection[*struct{}, *struct{}](name).
+			On(1, func(_ context.Context, event events.Event[*struct{}], executor *struct{}) error {
+				assert.Same(t, expectedExecutor, executor)
+				mu.Lock()
+				defer mu.Unlock()
+				handled[name] = append(handled[name], event.Type)
+				return err
+			})
+	}
+	expectBatch := func(tc ProjectorTestCase, name string) {
+		eventStoreMock.EXPECT().
+			GetSubscription(tc.ctx, "projection:"+name, expectedExecutor).
+			Return(subscriptions.NewSubscription("projection:"+name, expectedLastSequenceID), nil)
+		eventStoreMock.EXPECT().
+			GetTransactions(tc.ctx, expectedLastSequenceID, expectedBatchSize, expectedExecutor).
+			Return(expectedBatch[:1], nil)
+		eventStoreMock.EXPECT().
+			GetUnhandledEvents(tc.ctx, firstAggregateID, expectedLastSequenceID, int64(25), expectedExecutor).
+			Return(firstEvents, nil)
+	}
+	testCases := []ProjectorTestCase{
+		{
+			description: "Если при вызове метода Project обработчик проекции вернул ошибку, то должна вернуться ошибка с откатом транзакции без сдвига позиции проекции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc ProjectorTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				expectBatch(tc, "first")
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, errExpected)
+			},
+			projections: []*projections.Projection[*struct{}, *struct{}]{newProjection("first", errExpected)},
+			handled:     map[string][]int{"first": {1}},
+		},
+		{
+			description: "Если не удалось откатить транзакцию проекции, то должны вернуться и ошибка обработчика, и ошибка отката", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc ProjectorTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				expectBatch(tc, "first")
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(errRollback)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, errExpected)
+				assert.ErrorIs(t, actual, errRollback)
+			},
+			projections: []*projections.Projection[*struct{}, *struct{}]{newProjection("first", errExpected)},
+			handled:     map[string][]int{"first": {1}},
+		},
+		{
+			description: "Если позиция проекции заблокирована другим экземпляром, то метод Project должен пропускать проекцию без ошибки", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc ProjectorTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, "projection:first", expectedExecutor).
+					Return(nil, fmt.Errorf("%w: projection:first", coreRepositories.ErrSubscriptionLocked))
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+			projections: []*projections.Projection[*struct{}, *struct{}]{newProjection("first", nil)},
+			handled:     map[string][]int{},
+		},
+		{
+			description: "При вызове метода Project события должны передаваться только обработчикам зарегистрированных типов, а позиция проекции сдвигаться", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc ProjectorTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				expectBatch(tc, "first")
+				eventStoreMock.EXPECT().
+					UpdateSubscription(tc.ctx, subscriptions.NewSubscription("projection:first", 25), expectedExecutor).
+					Return(nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+			projections: []*projections.Projection[*struct{}, *struct{}]{newProjection("first", nil)},
+			handled:     map[string][]int{"first": {1}},
+		},
+		{
+			description: "При вызове метода Project каждая проекция должна обрабатывать журнал событий пакетами от собственной позиции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc ProjectorTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Times(3)
+				expectBatch(tc, "first")
+				eventStoreMock.EXPECT().
+					UpdateSubscription(tc.ctx, subscriptions.NewSubscription("projection:first", 25), expectedExecutor).
+					Return(nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, "projection:second", expectedExecutor).
+					Return(subscriptions.NewSubscription("projection:second", secondLastSequenceID), nil).
+					Once()
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, secondLastSequenceID, expectedBatchSize, expectedExecutor).
+					Return(expectedBatch, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, firstAggregateID, secondLastSequenceID, int64(25), expectedExecutor).
+					Return(firstEvents, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, secondAggregateID, int64(25), int64(27), expectedExecutor).
+					Return(secondEvents, nil)
+				eventStoreMock.EXPECT().
+					UpdateSubscription(tc.ctx, subscriptions.NewSubscription("projection:second", 27), expectedExecutor).
+					Return(nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, "projection:second", expectedExecutor).
+					Return(subscriptions.NewSubscription("projection:second", 27), nil).
+					Once()
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, int64(27), expectedBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{}, nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil).Times(3)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+			projections: []*projections.Projection[*struct{}, *struct{}]{
+				newProjection("first", nil),
+				newProjection("second", nil),
+			},
+			handled: map[string][]int{"first": {1}, "second": {1, 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				handled = make(map[string][]int)
+				tc.mockAssertion(tc)
+
+				projector := services.NewProjector[*struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					tc.projections,
+					slog.Default(),
+					services.WithProjectorBatchSize[*struct{}, *struct{}, *struct{}](expectedBatchSize),
+				)
+				err := projector.Project(tc.ctx)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+				assert.Equal(t, tc.handled, handled)
+			})
+	}
+}
+
+func TestProjector_LagMethod(t *testing.T) {
+	eventStoreMock := repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+	ctx := context.Background()
+	executor := &struct{}{}
+	eventStoreMock.EXPECT().Begin(ctx).Return(executor, nil)
+	eventStoreMock.EXPECT().
+		GetSubscriptions(ctx, executor).
+		Return([]*subscriptions.Subscription{
+			subscriptions.NewSubscription("first", 99),
+			subscriptions.NewSubscription("projection:first", 24),
+		}, nil)
+	eventStoreMock.EXPECT().CountTransactions(ctx, int64(24), executor).Return(3, nil)
+	eventStoreMock.EXPECT().CountTransactions(ctx, int64(0), executor).Return(10, nil)
+	eventStoreMock.EXPECT().Rollback(ctx, executor).Return(nil)
+
+	projector := services.NewProjector[*struct{}, *struct{}, *struct{}](
+		eventStoreMock,
+		[]*projections.Projection[*struct{}, *struct{}]{
+			projections.NewProjection[*struct{}, *struct{}]("first"),
+			projections.NewProjection[*struct{}, *struct{}]("second"),
+		},
+		slog.Default(),
+	)
+	lags, err := projector.Lag(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []projections.Lag{
+		projections.NewLag("first", 24, 3),
+		projections.NewLag("second", 0, 10),
+	}, lags)
+}
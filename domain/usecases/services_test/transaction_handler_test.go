@@ -10,6 +10,7 @@ import (
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
 	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
 	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	coreRepositories "github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
 	mockEntities "github.com/alex-fullstack/event-sourcingo/mocks/entities"
 	"github.com/alex-fullstack/event-sourcingo/mocks/repositories"
@@ -30,10 +31,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 	var (
 		eventStoreMock        *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
 		aggregateProviderMock *mockEntities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
-		eventHandlerMock      *mockServices.MockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}]
+		eventHandlerMock      *mockServices.MockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}]
 		errExpected           = errors.New(
 			"test error",
 		)
+		errRollback      = errors.New("rollback error")
 		expectedExecutor = &struct{}{}
 		expectedID       = uuid.New()
 		expectedEvents   = []events.Event[*struct{}]{
@@ -47,7 +49,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			SequenceID:  expectedLastSequenceID + 1,
 			AggregateID: expectedID,
 		}
-		expectedSubscription = &subscriptions.Subscription{LastSequenceID: expectedLastSequenceID}
+		expectedSubscriptionName = "test-subscription"
+		expectedSubscription     = subscriptions.NewSubscription(
+			expectedSubscriptionName,
+			expectedLastSequenceID,
+		)
 	)
 	testCases := []TransactionHandlerTestCase{
 		{
@@ -66,7 +72,7 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			mockAssertion: func(tc TransactionHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
 				eventStoreMock.EXPECT().
-					GetSubscription(tc.ctx, expectedExecutor).
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
 					Return(nil, errExpected)
 				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
 			},
@@ -74,6 +80,35 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 				assert.Equal(t, errExpected, actual)
 			},
 		},
+		{
+			description: "Если при вызове метода Handle не удалось откатить транзакцию, то должны вернуться обе ошибки",
+			ctx:         context.Background(),
+			mockAssertion: func(tc TransactionHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(nil, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(errRollback)
+			},
+			dataAssertion: func(actual error) {
+				assert.ErrorIs(t, actual, errExpected)
+				assert.ErrorIs(t, actual, errRollback)
+			},
+		},
+		{
+			description: "Если подписка заблокирована другим экземпляром, то метод Handle должен завершаться без ошибки",
+			ctx:         context.Background(),
+			mockAssertion: func(tc TransactionHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(nil, coreRepositories.ErrSubscriptionLocked)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
 		{
 			description: "Если при вызове метода Handle не удалось получить данные агрегата, то должна вернуться ошибка с откатом транзакции", //nolint:lll
 			ctx:         context.Background(),
@@ -81,8 +116,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			mockAssertion: func(tc TransactionHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
 				eventStoreMock.EXPECT().
-					GetSubscription(tc.ctx, expectedExecutor).
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
 					Return(expectedSubscription, nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, services.DefaultBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{tc.transaction}, nil)
 				eventStoreMock.EXPECT().
 					GetUnhandledEvents(
 						tc.ctx,
@@ -105,8 +143,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			mockAssertion: func(tc TransactionHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
 				eventStoreMock.EXPECT().
-					GetSubscription(tc.ctx, expectedExecutor).
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
 					Return(expectedSubscription, nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, services.DefaultBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{tc.transaction}, nil)
 				eventStoreMock.EXPECT().
 					GetUnhandledEvents(
 						tc.ctx,
@@ -121,7 +162,7 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 					Return(0, nil, nil)
 				aggregateProviderMock.EXPECT().ID().Return(expectedID)
 				eventHandlerMock.EXPECT().
-					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents).
+					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
 					Return(errExpected)
 				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
 			},
@@ -136,8 +177,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			mockAssertion: func(tc TransactionHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
 				eventStoreMock.EXPECT().
-					GetSubscription(tc.ctx, expectedExecutor).
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
 					Return(expectedSubscription, nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, services.DefaultBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{tc.transaction}, nil)
 				eventStoreMock.EXPECT().
 					GetUnhandledEvents(
 						tc.ctx, expectedID,
@@ -151,14 +195,15 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 					Return(0, nil, nil)
 				aggregateProviderMock.EXPECT().ID().Return(expectedID)
 				eventHandlerMock.EXPECT().
-					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents).
+					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
 					Return(nil)
 				eventStoreMock.EXPECT().
 					UpdateSubscription(
 						tc.ctx,
-						&subscriptions.Subscription{
-							LastSequenceID: tc.transaction.SequenceID,
-						}, expectedExecutor).
+						subscriptions.NewSubscription(
+							expectedSubscriptionName,
+							tc.transaction.SequenceID,
+						), expectedExecutor).
 					Return(errExpected)
 				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
 			},
@@ -173,8 +218,11 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			mockAssertion: func(tc TransactionHandlerTestCase) {
 				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
 				eventStoreMock.EXPECT().
-					GetSubscription(tc.ctx, expectedExecutor).
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
 					Return(expectedSubscription, nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, services.DefaultBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{tc.transaction}, nil)
 				eventStoreMock.EXPECT().
 					GetUnhandledEvents(
 						tc.ctx,
@@ -189,14 +237,15 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 					Return(0, nil, nil)
 				aggregateProviderMock.EXPECT().ID().Return(expectedID)
 				eventHandlerMock.EXPECT().
-					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents).
+					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
 					Return(nil)
 				eventStoreMock.EXPECT().
 					UpdateSubscription(
 						tc.ctx,
-						&subscriptions.Subscription{
-							LastSequenceID: tc.transaction.SequenceID,
-						}, expectedExecutor).
+						subscriptions.NewSubscription(
+							expectedSubscriptionName,
+							tc.transaction.SequenceID,
+						), expectedExecutor).
 					Return(nil)
 				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
 			},
@@ -216,7 +265,7 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](
 					t,
 				)
-				eventHandlerMock = mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}](
+				eventHandlerMock = mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
 					t,
 				)
 				tc.mockAssertion(tc)
@@ -224,6 +273,7 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 				handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
 					eventStoreMock,
 					eventHandlerMock,
+					expectedSubscriptionName,
 					slog.Default(),
 				)
 				err := handler.Handle(
@@ -240,3 +290,463 @@ func TestTransactionHandler_HandleMethod(t *testing.T) {
 			})
 	}
 }
+
+type TransactionHandlerCatchUpTestCase struct {
+	description   string
+	ctx           context.Context
+	mockAssertion func(tc TransactionHandlerCatchUpTestCase)
+	dataAssertion func(actual error)
+}
+
+func TestTransactionHandler_CatchUpMethod(t *testing.T) {
+	var (
+		eventStoreMock        *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		aggregateProviderMock *mockEntities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		eventHandlerMock      *mockServices.MockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected           = errors.New("test error")
+		expectedExecutor      = &struct{}{}
+		expectedBatchSize     = 2
+		firstAggregateID      = uuid.New()
+		secondAggregateID     = uuid.New()
+		firstEvents           = []events.Event[*struct{}]{{AggregateID: firstAggregateID}}
+		secondEvents          = []events.Event[*struct{}]{{AggregateID: secondAggregateID}}
+		expectedBatch         = []*transactions.Transaction{
+			transactions.NewTransaction(uuid.New(), firstAggregateID, 25),
+			transactions.NewTransaction(uuid.New(), secondAggregateID, 27),
+		}
+		expectedSubscriptionName       = "test-subscription"
+		expectedLastSequenceID   int64 = 24
+	)
+	testCases := []TransactionHandlerCatchUpTestCase{
+		{
+			description: "Если при вызове метода CatchUp не удалось получить список транзакций, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc TransactionHandlerCatchUpTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(subscriptions.NewSubscription(expectedSubscriptionName, expectedLastSequenceID), nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, expectedBatchSize, expectedExecutor).
+					Return(nil, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода CatchUp нет новых транзакций, то подписка не должна обновляться",
+			ctx:         context.Background(),
+			mockAssertion: func(tc TransactionHandlerCatchUpTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(subscriptions.NewSubscription(expectedSubscriptionName, expectedLastSequenceID), nil)
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, expectedBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{}, nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "При вызове метода CatchUp транзакции всех агрегатов должны обрабатываться пакетами в порядке их последовательности", //nolint:lll
+			ctx:         context.Background(),
+			mockAssertion: func(tc TransactionHandlerCatchUpTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil).Twice()
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(subscriptions.NewSubscription(expectedSubscriptionName, expectedLastSequenceID), nil).
+					Once()
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, expectedLastSequenceID, expectedBatchSize, expectedExecutor).
+					Return(expectedBatch, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, firstAggregateID, expectedLastSequenceID, int64(25), expectedExecutor).
+					Return(firstEvents, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, secondAggregateID, int64(25), int64(27), expectedExecutor).
+					Return(secondEvents, nil)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, firstAggregateID, &firstEvents[0].Version, expectedExecutor).
+					Return(0, nil, nil)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, secondAggregateID, &secondEvents[0].Version, expectedExecutor).
+					Return(0, nil, nil)
+				aggregateProviderMock.EXPECT().ID().Return(firstAggregateID).Once()
+				aggregateProviderMock.EXPECT().ID().Return(secondAggregateID).Once()
+				eventHandlerMock.EXPECT().
+					HandleEvents(tc.ctx, aggregateProviderMock, firstEvents, expectedExecutor).
+					Return(nil)
+				eventHandlerMock.EXPECT().
+					HandleEvents(tc.ctx, aggregateProviderMock, secondEvents, expectedExecutor).
+					Return(nil)
+				eventStoreMock.EXPECT().
+					UpdateSubscription(
+						tc.ctx,
+						subscriptions.NewSubscription(expectedSubscriptionName, 27),
+						expectedExecutor,
+					).
+					Return(nil)
+				eventStoreMock.EXPECT().
+					GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+					Return(subscriptions.NewSubscription(expectedSubscriptionName, 27), nil).
+					Once()
+				eventStoreMock.EXPECT().
+					GetTransactions(tc.ctx, int64(27), expectedBatchSize, expectedExecutor).
+					Return([]*transactions.Transaction{}, nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil).Twice()
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				aggregateProviderMock = mockEntities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]( //nolint:lll
+					t,
+				)
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](
+					t,
+				)
+				eventHandlerMock = mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				tc.mockAssertion(tc)
+
+				handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					eventHandlerMock,
+					expectedSubscriptionName,
+					slog.Default(),
+					services.WithBatchSize[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](expectedBatchSize),
+				)
+				err := handler.CatchUp(
+					tc.ctx,
+					func(_ uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+						return aggregateProviderMock
+					},
+				)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestTransactionHandler_HandleTransactionMethod(t *testing.T) {
+	var (
+		eventStoreMock        *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		aggregateProviderMock *mockEntities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		eventHandlerMock      *mockServices.MockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected           = errors.New("test error")
+		expectedExecutor      = &struct{}{}
+		expectedID            = uuid.New()
+		expectedEvents        = []events.Event[*struct{}]{{AggregateID: expectedID}}
+		expectedTransaction   = transactions.NewTransaction(uuid.New(), expectedID, 25)
+	)
+	testCases := []TransactionHandlerTestCase{
+		{
+			description: "При вызове метода HandleTransaction должны обрабатываться только события переданной транзакции без обращения к подписке", //nolint:lll
+			ctx:         context.Background(),
+			transaction: expectedTransaction,
+			mockAssertion: func(tc TransactionHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, expectedID, int64(24), int64(25), expectedExecutor).
+					Return(expectedEvents, nil)
+				eventStoreMock.EXPECT().
+					GetSnapshot(tc.ctx, expectedID, &expectedEvents[0].Version, expectedExecutor).
+					Return(0, nil, nil)
+				aggregateProviderMock.EXPECT().ID().Return(expectedID)
+				eventHandlerMock.EXPECT().
+					HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
+					Return(nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+			},
+		},
+		{
+			description: "Если при вызове метода HandleTransaction не удалось обработать события, то должна вернуться ошибка с откатом транзакции", //nolint:lll
+			ctx:         context.Background(),
+			transaction: expectedTransaction,
+			mockAssertion: func(tc TransactionHandlerTestCase) {
+				eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+				eventStoreMock.EXPECT().
+					GetUnhandledEvents(tc.ctx, expectedID, int64(24), int64(25), expectedExecutor).
+					Return(nil, errExpected)
+				eventStoreMock.EXPECT().Rollback(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.Equal(t, errExpected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				aggregateProviderMock = mockEntities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]( //nolint:lll
+					t,
+				)
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				eventHandlerMock = mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				tc.mockAssertion(tc)
+
+				handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					eventHandlerMock,
+					"test-subscription",
+					slog.Default(),
+				)
+				err := handler.HandleTransaction(
+					tc.ctx,
+					tc.transaction,
+					func(_ uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+						return aggregateProviderMock
+					},
+				)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+type TransactionHandlerRegistryTestCase struct {
+	description   string
+	ctx           context.Context
+	transaction   *transactions.Transaction
+	mockAssertion func(tc TransactionHandlerRegistryTestCase)
+	dataAssertion func(actual error)
+}
+
+func TestTransactionHandler_WithAggregateRegistry(t *testing.T) {
+	var (
+		eventStoreMock        *repositories.MockEventStore[*struct{}, *struct{}, *struct{}]
+		aggregateProviderMock *mockEntities.MockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]
+		eventHandlerMock      *mockServices.MockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}]
+		expectedExecutor      = &struct{}{}
+		expectedID            = uuid.New()
+		expectedEvents        = []events.Event[*struct{}]{
+			{AggregateID: expectedID},
+		}
+		expectedLastSequenceID   int64 = 24
+		expectedSubscriptionName       = "test-subscription"
+		expectedSubscription           = subscriptions.NewSubscription(
+			expectedSubscriptionName,
+			expectedLastSequenceID,
+		)
+		built      []uuid.UUID
+		fallback   []uuid.UUID
+		providerFn = func(id uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+			fallback = append(fallback, id)
+			return aggregateProviderMock
+		}
+	)
+	expectBatch := func(tc TransactionHandlerRegistryTestCase) {
+		eventStoreMock.EXPECT().Begin(tc.ctx).Return(expectedExecutor, nil)
+		eventStoreMock.EXPECT().
+			GetSubscription(tc.ctx, expectedSubscriptionName, expectedExecutor).
+			Return(expectedSubscription, nil)
+		eventStoreMock.EXPECT().
+			GetTransactions(tc.ctx, expectedLastSequenceID, services.DefaultBatchSize, expectedExecutor).
+			Return([]*transactions.Transaction{tc.transaction}, nil)
+		eventStoreMock.EXPECT().
+			GetUnhandledEvents(
+				tc.ctx,
+				expectedID,
+				expectedLastSequenceID,
+				tc.transaction.SequenceID,
+				expectedExecutor,
+			).
+			Return(expectedEvents, nil)
+	}
+	expectHandled := func(tc TransactionHandlerRegistryTestCase) {
+		expectBatch(tc)
+		eventStoreMock.EXPECT().
+			GetSnapshot(tc.ctx, expectedID, &expectedEvents[0].Version, expectedExecutor).
+			Return(0, nil, nil)
+		aggregateProviderMock.EXPECT().ID().Return(expectedID)
+		eventHandlerMock.EXPECT().
+			HandleEvents(tc.ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
+			Return(nil)
+		eventStoreMock.EXPECT().
+			UpdateSubscription(
+				tc.ctx,
+				subscriptions.NewSubscription(expectedSubscriptionName, tc.transaction.SequenceID),
+				expectedExecutor,
+			).
+			Return(nil)
+		eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+	}
+	testCases := []TransactionHandlerRegistryTestCase{
+		{
+			description: "При вызове метода Handle агрегат должен создаваться фабрикой, зарегистрированной для типа агрегата транзакции", //nolint:lll
+			ctx:         context.Background(),
+			transaction: &transactions.Transaction{
+				ID:            uuid.New(),
+				SequenceID:    expectedLastSequenceID + 1,
+				AggregateID:   expectedID,
+				AggregateType: "role",
+			},
+			mockAssertion: expectHandled,
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+				assert.Equal(t, []uuid.UUID{expectedID}, built)
+			},
+		},
+		{
+			description: "Если тип агрегата транзакции не сохранен, то агрегат должен создаваться переданной функцией без обращения к реестру", //nolint:lll
+			ctx:         context.Background(),
+			transaction: &transactions.Transaction{
+				ID:          uuid.New(),
+				SequenceID:  expectedLastSequenceID + 1,
+				AggregateID: expectedID,
+			},
+			mockAssertion: expectHandled,
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+				assert.Empty(t, built)
+				assert.Equal(t, []uuid.UUID{expectedID}, fallback)
+			},
+		},
+		{
+			description: "Если для типа агрегата транзакции не зарегистрирована фабрика, то транзакция должна пропускаться со сдвигом подписки, не блокируя ее", //nolint:lll
+			ctx:         context.Background(),
+			transaction: &transactions.Transaction{
+				ID:            uuid.New(),
+				SequenceID:    expectedLastSequenceID + 1,
+				AggregateID:   expectedID,
+				AggregateType: "policy",
+			},
+			mockAssertion: func(tc TransactionHandlerRegistryTestCase) {
+				expectBatch(tc)
+				eventStoreMock.EXPECT().
+					UpdateSubscription(
+						tc.ctx,
+						subscriptions.NewSubscription(expectedSubscriptionName, tc.transaction.SequenceID),
+						expectedExecutor,
+					).
+					Return(nil)
+				eventStoreMock.EXPECT().Commit(tc.ctx, expectedExecutor).Return(nil)
+			},
+			dataAssertion: func(actual error) {
+				assert.NoError(t, actual)
+				assert.Empty(t, built)
+				assert.Empty(t, fallback)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				aggregateProviderMock = mockEntities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}]( //nolint:lll
+					t,
+				)
+				eventStoreMock = repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+				eventHandlerMock = mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					t,
+				)
+				built, fallback = nil, nil
+				tc.mockAssertion(tc)
+
+				registry := entities.NewAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}]()
+				factory := func(id uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+					built = append(built, id)
+					return aggregateProviderMock
+				}
+				assert.NoError(t, registry.Register("role", factory))
+				assert.ErrorIs(t, registry.Register("role", factory), entities.ErrDuplicateAggregateType)
+
+				handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					eventStoreMock,
+					eventHandlerMock,
+					expectedSubscriptionName,
+					slog.Default(),
+					services.WithAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](registry),
+				)
+				err := handler.Handle(tc.ctx, tc.transaction, providerFn)
+
+				if tc.dataAssertion != nil {
+					tc.dataAssertion(err)
+				}
+			})
+	}
+}
+
+func TestTransactionHandler_HandleTransactionWithAggregateRegistry(t *testing.T) {
+	ctx := context.Background()
+	expectedExecutor := &struct{}{}
+	expectedID := uuid.New()
+	expectedEvents := []events.Event[*struct{}]{{AggregateID: expectedID}}
+	transaction := transactions.NewTransaction(uuid.New(), expectedID, 25)
+
+	t.Run("Без функции создания агрегата и реестра должна возвращаться ошибка до открытия транзакции", func(t *testing.T) {
+		handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+			repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t),
+			mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](t),
+			"test-subscription",
+			slog.Default(),
+		)
+
+		assert.ErrorIs(t, handler.CatchUp(ctx, nil), services.ErrAggregateProviderMissing)
+		assert.ErrorIs(t, handler.HandleTransaction(ctx, transaction, nil), services.ErrAggregateProviderMissing)
+	})
+	t.Run("Тип агрегата транзакции без типа должен загружаться из хранилища для выбора фабрики реестра", func(t *testing.T) { //nolint:lll
+		eventStoreMock := repositories.NewMockEventStore[*struct{}, *struct{}, *struct{}](t)
+		eventHandlerMock := mockServices.NewMockEventHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](t)
+		aggregateProviderMock := mockEntities.NewMockAggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}](t)
+		eventStoreMock.EXPECT().Begin(ctx).Return(expectedExecutor, nil)
+		eventStoreMock.EXPECT().
+			GetUnhandledEvents(ctx, expectedID, int64(24), int64(25), expectedExecutor).
+			Return(expectedEvents, nil)
+		eventStoreMock.EXPECT().
+			GetAggregateTypes(ctx, []uuid.UUID{expectedID}, expectedExecutor).
+			Return(map[uuid.UUID]string{expectedID: "role"}, nil)
+		eventStoreMock.EXPECT().
+			GetSnapshot(ctx, expectedID, &expectedEvents[0].Version, expectedExecutor).
+			Return(0, nil, nil)
+		aggregateProviderMock.EXPECT().ID().Return(expectedID)
+		eventHandlerMock.EXPECT().
+			HandleEvents(ctx, aggregateProviderMock, expectedEvents, expectedExecutor).
+			Return(nil)
+		eventStoreMock.EXPECT().Commit(ctx, expectedExecutor).Return(nil)
+		registry := entities.NewAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}]()
+		var built []uuid.UUID
+		assert.NoError(t, registry.Register(
+			"role",
+			func(id uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+				built = append(built, id)
+				return aggregateProviderMock
+			},
+		))
+		handler := services.NewTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+			eventStoreMock,
+			eventHandlerMock,
+			"test-subscription",
+			slog.Default(),
+			services.WithAggregateRegistry[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](registry),
+		)
+
+		assert.NoError(t, handler.HandleTransaction(ctx, transaction, nil))
+		assert.Equal(t, []uuid.UUID{expectedID}, built)
+		assert.Empty(t, transaction.AggregateType)
+	})
+}
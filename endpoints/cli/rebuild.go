@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+const RebuildCommandName = "rebuild-projections"
+
+type rebuildFlags struct {
+	ids           []string
+	aggregateType string
+	after         string
+	state         string
+	workers       int
+	pageSize      int
+}
+
+func NewRebuildCmd(rebuilder services.ProjectionRebuilder) *cobra.Command {
+	flags := &rebuildFlags{}
+	cmd := &cobra.Command{
+		Use:   RebuildCommandName,
+		Short: "Rebuild aggregate projections from the event store",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runRebuild(cmd.Context(), rebuilder, flags, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringSliceVar(&flags.ids, "ids", nil, "comma separated aggregate IDs to rebuild, all aggregates if empty")
+	cmd.Flags().StringVar(&flags.aggregateType, "aggregate-type", "", "rebuild only aggregates of the given type")
+	cmd.Flags().StringVar(&flags.after, "after", "", "rebuild only aggregates with IDs greater than the given one")
+	cmd.Flags().StringVar(&flags.state, "state", "", "file keeping the rebuild cursor to resume after interruption")
+	cmd.Flags().IntVar(
+		&flags.workers,
+		"workers",
+		services.DefaultRebuildWorkers,
+		"number of aggregates rebuilt concurrently",
+	)
+	cmd.Flags().IntVar(&flags.pageSize, "page-size", services.DefaultBatchSize, "number of aggregates loaded per page")
+	return cmd
+}
+
+func runRebuild(
+	ctx context.Context,
+	rebuilder services.ProjectionRebuilder,
+	flags *rebuildFlags,
+	out io.Writer,
+) error {
+	opts := services.RebuildOptions{
+		AggregateType: flags.aggregateType,
+		Workers:       flags.workers,
+		PageSize:      flags.pageSize,
+	}
+	var err error
+	if opts.IDs, err = parseIDs(flags.ids); err != nil {
+		return err
+	}
+	cursor := flags.after
+	if flags.state != "" && cursor == "" {
+		if cursor, err = readState(flags.state); err != nil {
+			return err
+		}
+	}
+	if cursor != "" {
+		if opts.After, err = uuid.Parse(cursor); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "resuming after %s\n", opts.After)
+	}
+	opts.Progress = func(progress services.RebuildProgress) {
+		_, _ = fmt.Fprintf(out, "rebuilt %d aggregates, cursor %s\n", progress.Processed, progress.Cursor)
+		if flags.state == "" {
+			return
+		}
+		if stateErr := os.WriteFile(flags.state, []byte(progress.Cursor.String()), 0o600); stateErr != nil {
+			_, _ = fmt.Fprintf(out, "failed to save rebuild state: %s\n", stateErr)
+		}
+	}
+
+	progress, err := rebuilder.Rebuild(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("rebuild stopped after %d aggregates at cursor %s: %w", progress.Processed, progress.Cursor, err)
+	}
+	_, _ = fmt.Fprintf(out, "rebuild completed, %d aggregates\n", progress.Processed)
+	if flags.state != "" {
+		return os.Remove(flags.state)
+	}
+	return nil
+}
+
+func parseIDs(raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, part := range raw {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		id, err := uuid.Parse(part)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return ids, nil
+}
+
+func readState(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
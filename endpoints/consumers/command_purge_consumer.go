@@ -0,0 +1,89 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+)
+
+const DefaultCommandPurgeInterval = time.Hour
+
+type CommandPurgeConsumerOption func(*commandPurgeConsumer)
+
+func WithCommandPurgeInterval(interval time.Duration) CommandPurgeConsumerOption {
+	return func(pc *commandPurgeConsumer) {
+		pc.interval = interval
+	}
+}
+
+type commandPurgeConsumer struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	purger   services.CommandPurger
+	interval time.Duration
+	done     chan struct{}
+	log      *slog.Logger
+}
+
+func NewCommandPurgeConsumer(
+	ctx context.Context,
+	purger services.CommandPurger,
+	opts ...CommandPurgeConsumerOption,
+) endpoints.EndpointStarter {
+	ctx, cancel := context.WithCancel(ctx)
+	pc := &commandPurgeConsumer{
+		ctx:      ctx,
+		cancel:   cancel,
+		purger:   purger,
+		interval: DefaultCommandPurgeInterval,
+		done:     make(chan struct{}),
+		log:      slog.Default().With(slog.String("consumer", "command-purge")),
+	}
+	for _, opt := range opts {
+		opt(pc)
+	}
+
+	return &consumer{
+		Endpoint: endpoints.NewEndpoint(
+			pc.start,
+			pc.stop,
+			pc.log,
+		),
+	}
+}
+
+func (pc *commandPurgeConsumer) start() error {
+	defer close(pc.done)
+	ticker := time.NewTicker(pc.interval)
+	defer ticker.Stop()
+	for {
+		purged, err := pc.purger.Purge(pc.ctx)
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			pc.log.ErrorContext(pc.ctx, err.Error())
+		case purged > 0:
+			pc.log.DebugContext(pc.ctx, "command ids purged", slog.Int64("purged", purged))
+		}
+		select {
+		case <-pc.ctx.Done():
+			return http.ErrServerClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (pc *commandPurgeConsumer) stop(ctx context.Context) error {
+	pc.cancel()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-pc.done:
+		pc.log.InfoContext(ctx, "command purge consumer shutting down successfully")
+		return nil
+	}
+}
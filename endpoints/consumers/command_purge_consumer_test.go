@@ -0,0 +1,92 @@
+package consumers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/endpoints/consumers"
+	"github.com/alex-fullstack/event-sourcingo/mocks/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type CommandPurgeConsumerTestCase struct {
+	description   string
+	interval      time.Duration
+	expectedCalls int
+	mockAssertion func(calls chan<- struct{})
+}
+
+func TestCommandPurgeConsumer_GracefulStartMethod(t *testing.T) {
+	var (
+		purgerMock  *services.MockCommandPurger
+		errExpected = errors.New("test error")
+		purge       = func(calls chan<- struct{}, purged int64, err error) func(context.Context) (int64, error) {
+			return func(context.Context) (int64, error) {
+				select {
+				case calls <- struct{}{}:
+				default:
+				}
+				return purged, err
+			}
+		}
+	)
+	testCases := []CommandPurgeConsumerTestCase{
+		{
+			description:   "При запуске потребителя должна однократно выполняться очистка идентификаторов команд",
+			interval:      time.Hour,
+			expectedCalls: 1,
+			mockAssertion: func(calls chan<- struct{}) {
+				purgerMock.EXPECT().Purge(mock.Anything).RunAndReturn(purge(calls, 1, nil)).Once()
+			},
+		},
+		{
+			description:   "Если очистка завершилась ошибкой, то потребитель должен повторить ее по истечении интервала",
+			interval:      10 * time.Millisecond,
+			expectedCalls: 2,
+			mockAssertion: func(calls chan<- struct{}) {
+				purgerMock.EXPECT().Purge(mock.Anything).RunAndReturn(purge(calls, 0, errExpected))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				purgerMock = services.NewMockCommandPurger(t)
+				calls := make(chan struct{}, tc.expectedCalls)
+				tc.mockAssertion(calls)
+
+				consumer := consumers.NewCommandPurgeConsumer(
+					context.Background(),
+					purgerMock,
+					consumers.WithCommandPurgeInterval(tc.interval),
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				timeout := consumerWaitTimeout
+				result := make(chan error, 1)
+				go func() {
+					result <- consumer.GracefulStart(ctx, &timeout)
+				}()
+				for range tc.expectedCalls {
+					select {
+					case <-calls:
+					case <-time.After(consumerWaitTimeout):
+						require.FailNow(t, "command purger was not called")
+					}
+				}
+				cancel()
+				select {
+				case err := <-result:
+					assert.NoError(t, err)
+				case <-time.After(2 * consumerWaitTimeout):
+					require.FailNow(t, "consumer did not stop after the start context was cancelled")
+				}
+			},
+		)
+	}
+}
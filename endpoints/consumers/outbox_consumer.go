@@ -0,0 +1,123 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+)
+
+const (
+	DefaultRelayInterval      = time.Second
+	defaultRelayBackoff       = 100 * time.Millisecond
+	defaultRelayMaxBackoff    = 30 * time.Second
+	defaultRelayBackoffJitter = 100 * time.Millisecond
+)
+
+type OutboxConsumerOption func(*outboxConsumer)
+
+func WithRelayInterval(interval time.Duration) OutboxConsumerOption {
+	return func(oc *outboxConsumer) {
+		oc.interval = interval
+	}
+}
+
+func WithRelayRetry(policy services.RetryPolicy) OutboxConsumerOption {
+	return func(oc *outboxConsumer) {
+		oc.retry = policy
+	}
+}
+
+type outboxConsumer struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	relay    services.OutboxRelay
+	interval time.Duration
+	retry    services.RetryPolicy
+	done     chan struct{}
+	log      *slog.Logger
+}
+
+func NewOutboxConsumer(
+	ctx context.Context,
+	relay services.OutboxRelay,
+	opts ...OutboxConsumerOption,
+) endpoints.EndpointStarter {
+	ctx, cancel := context.WithCancel(ctx)
+	oc := &outboxConsumer{
+		ctx:      ctx,
+		cancel:   cancel,
+		relay:    relay,
+		interval: DefaultRelayInterval,
+		retry: services.NewRetryPolicy(
+			0,
+			defaultRelayBackoff,
+			defaultRelayMaxBackoff,
+			defaultRelayBackoffJitter,
+		),
+		done: make(chan struct{}),
+		log:  slog.Default().With(slog.String("consumer", "outbox")),
+	}
+	for _, opt := range opts {
+		opt(oc)
+	}
+
+	return &consumer{
+		Endpoint: endpoints.NewEndpoint(
+			oc.start,
+			oc.stop,
+			oc.log,
+		),
+	}
+}
+
+func (oc *outboxConsumer) start() error {
+	defer close(oc.done)
+	attempt := 0
+	for {
+		relayed, err := oc.relay.Relay(oc.ctx)
+		if oc.ctx.Err() != nil {
+			return http.ErrServerClosed
+		}
+		switch {
+		case err != nil:
+			attempt++
+			oc.log.ErrorContext(oc.ctx, err.Error(), slog.Int("attempt", attempt))
+			if err = oc.retry.Wait(oc.ctx, attempt); err != nil {
+				return http.ErrServerClosed
+			}
+		case relayed > 0:
+			attempt = 0
+		default:
+			attempt = 0
+			if err = oc.wait(); err != nil {
+				return http.ErrServerClosed
+			}
+		}
+	}
+}
+
+func (oc *outboxConsumer) wait() error {
+	timer := time.NewTimer(oc.interval)
+	defer timer.Stop()
+	select {
+	case <-oc.ctx.Done():
+		return oc.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (oc *outboxConsumer) stop(ctx context.Context) error {
+	oc.cancel()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-oc.done:
+		oc.log.InfoContext(ctx, "outbox consumer shutting down successfully")
+		return nil
+	}
+}
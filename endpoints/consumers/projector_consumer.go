@@ -0,0 +1,106 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+)
+
+const DefaultProjectorInterval = time.Second
+
+type ProjectorConsumerOption func(*projectorConsumer)
+
+func WithProjectorInterval(interval time.Duration) ProjectorConsumerOption {
+	return func(pc *projectorConsumer) {
+		pc.interval = interval
+	}
+}
+
+type projectorConsumer struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	projector services.Projector
+	interval  time.Duration
+	done      chan struct{}
+	log       *slog.Logger
+}
+
+func NewProjectorConsumer(
+	ctx context.Context,
+	projector services.Projector,
+	opts ...ProjectorConsumerOption,
+) endpoints.EndpointStarter {
+	ctx, cancel := context.WithCancel(ctx)
+	pc := &projectorConsumer{
+		ctx:       ctx,
+		cancel:    cancel,
+		projector: projector,
+		interval:  DefaultProjectorInterval,
+		done:      make(chan struct{}),
+		log:       slog.Default().With(slog.String("consumer", "projector")),
+	}
+	for _, opt := range opts {
+		opt(pc)
+	}
+
+	return &consumer{
+		Endpoint: endpoints.NewEndpoint(
+			pc.start,
+			pc.stop,
+			pc.log,
+		),
+	}
+}
+
+func (pc *projectorConsumer) start() error {
+	defer close(pc.done)
+	ticker := time.NewTicker(pc.interval)
+	defer ticker.Stop()
+	for {
+		err := pc.projector.Project(pc.ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			pc.log.ErrorContext(pc.ctx, err.Error())
+		}
+		pc.reportLag()
+		select {
+		case <-pc.ctx.Done():
+			return http.ErrServerClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (pc *projectorConsumer) reportLag() {
+	lags, err := pc.projector.Lag(pc.ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			pc.log.ErrorContext(pc.ctx, err.Error())
+		}
+		return
+	}
+	for _, lag := range lags {
+		pc.log.DebugContext(
+			pc.ctx,
+			"projection lag",
+			slog.String("projection", lag.Name),
+			slog.Int64("position", lag.Position),
+			slog.Int64("pending", lag.Pending),
+		)
+	}
+}
+
+func (pc *projectorConsumer) stop(ctx context.Context) error {
+	pc.cancel()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-pc.done:
+		pc.log.InfoContext(ctx, "projector consumer shutting down successfully")
+		return nil
+	}
+}
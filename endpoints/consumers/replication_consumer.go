@@ -0,0 +1,44 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/postgresql"
+	"github.com/google/uuid"
+)
+
+func NewReplicationConsumer[T, S, P, K, E any](
+	ctx context.Context,
+	cfg *postgresql.ReplicationConfig,
+	handler services.TransactionHandler[T, S, P, K, E],
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+) endpoints.EndpointStarter {
+	logger := slog.Default().With(slog.String("slot", cfg.SlotName))
+	ctx, cancel := context.WithCancel(ctx)
+	feed := postgresql.NewReplicationFeed(
+		cfg,
+		func(ctx context.Context, transaction *transactions.Transaction) error {
+			return handler.HandleTransaction(ctx, transaction, providerFn)
+		},
+		func() context.Context {
+			return ctx
+		},
+		logger,
+	)
+
+	return &consumer{
+		Endpoint: endpoints.NewEndpoint(
+			feed.StartFeed,
+			func(ctx context.Context) error {
+				cancel()
+				return feed.Shutdown(ctx)
+			},
+			logger,
+		),
+	}
+}
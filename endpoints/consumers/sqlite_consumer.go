@@ -0,0 +1,48 @@
+package consumers
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/services"
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/sqlite"
+	"github.com/google/uuid"
+)
+
+func NewSQLiteConsumer[T, S, P, K any](
+	ctx context.Context,
+	db *sql.DB,
+	subscription string,
+	handler services.TransactionHandler[T, S, P, K, *sql.Tx],
+	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
+	opts ...sqlite.FeedOption,
+) endpoints.EndpointStarter {
+	logger := slog.Default().With(slog.String("subscription", subscription))
+	ctx, cancel := context.WithCancel(ctx)
+	feed := sqlite.NewFeed(
+		db,
+		func(ctx context.Context, transaction *transactions.Transaction) error {
+			return handler.Handle(ctx, transaction, providerFn)
+		},
+		func() context.Context {
+			return ctx
+		},
+		logger,
+		append([]sqlite.FeedOption{sqlite.WithFeedSubscription(subscription)}, opts...)...,
+	)
+
+	return &consumer{
+		Endpoint: endpoints.NewEndpoint(
+			feed.StartFeed,
+			func(ctx context.Context) error {
+				cancel()
+				return feed.Shutdown(ctx)
+			},
+			logger,
+		),
+	}
+}
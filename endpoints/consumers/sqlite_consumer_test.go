@@ -0,0 +1,153 @@
+package consumers_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/endpoints/consumers"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/sqlite"
+	"github.com/alex-fullstack/event-sourcingo/mocks/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type SQLiteConsumerTestCase struct {
+	description       string
+	mockAssertion     func(calls chan<- int64)
+	expectedSequences []int64
+}
+
+func TestSQLiteConsumer_GracefulStartMethod(t *testing.T) {
+	var (
+		handlerMock *services.MockTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *sql.Tx]
+		errExpected = errors.New("test error")
+		providerFn  = func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+			return nil
+		}
+		handle = func(calls chan<- int64, failures map[int64]int) func(
+			context.Context,
+			*transactions.Transaction,
+			func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+		) error {
+			return func(
+				_ context.Context,
+				transaction *transactions.Transaction,
+				_ func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+			) error {
+				select {
+				case calls <- transaction.SequenceID:
+				default:
+				}
+				if failures[transaction.SequenceID] > 0 {
+					failures[transaction.SequenceID]--
+					return errExpected
+				}
+				return nil
+			}
+		}
+	)
+	testCases := []SQLiteConsumerTestCase{
+		{
+			description: "Потребитель должен передавать обработчику все зафиксированные транзакции по порядку",
+			mockAssertion: func(calls chan<- int64) {
+				handlerMock.EXPECT().
+					Handle(mock.Anything, mock.Anything, mock.Anything).
+					RunAndReturn(handle(calls, nil)).
+					Times(2)
+			},
+			expectedSequences: []int64{1, 2},
+		},
+		{
+			description: "Если обработка транзакции завершилась ошибкой, то потребитель должен повторить ее",
+			mockAssertion: func(calls chan<- int64) {
+				handlerMock.EXPECT().
+					Handle(mock.Anything, mock.Anything, mock.Anything).
+					RunAndReturn(handle(calls, map[int64]int{1: 1})).
+					Times(3)
+			},
+			expectedSequences: []int64{1, 1, 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				store, err := sqlite.NewSQLiteDB[int, int](
+					context.Background(),
+					"file:"+filepath.Join(t.TempDir(), "events.db"),
+				)
+				require.NoError(t, err)
+				defer func() {
+					require.NoError(t, store.Close())
+				}()
+				for range 2 {
+					writeSQLiteTransaction(t, store)
+				}
+				handlerMock = services.NewMockTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *sql.Tx](t)
+				calls := make(chan int64, len(tc.expectedSequences))
+				tc.mockAssertion(calls)
+
+				consumer := consumers.NewSQLiteConsumer[*struct{}, *struct{}, *struct{}, *struct{}](
+					context.Background(),
+					store.DB(),
+					"test",
+					handlerMock,
+					providerFn,
+					sqlite.WithFeedInterval(10*time.Millisecond),
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				timeout := consumerWaitTimeout
+				result := make(chan error, 1)
+				go func() {
+					result <- consumer.GracefulStart(ctx, &timeout)
+				}()
+				delivered := make([]int64, 0, len(tc.expectedSequences))
+				for range tc.expectedSequences {
+					select {
+					case sequenceID := <-calls:
+						delivered = append(delivered, sequenceID)
+					case <-time.After(consumerWaitTimeout):
+						require.FailNow(t, "transaction handler was not called")
+					}
+				}
+				cancel()
+				select {
+				case err = <-result:
+					assert.NoError(t, err)
+				case <-time.After(2 * consumerWaitTimeout):
+					require.FailNow(t, "consumer did not stop after the start context was cancelled")
+				}
+				assert.Equal(t, tc.expectedSequences, delivered)
+			},
+		)
+	}
+}
+
+func writeSQLiteTransaction(t *testing.T, store *sqlite.SQLiteDB[int, int]) {
+	t.Helper()
+	ctx := context.Background()
+	id := uuid.New()
+	transaction := transactions.NewTransaction(uuid.New(), id, 0)
+	aggregate := entities.NewAggregate[int, int](
+		id,
+		10,
+		func(events.Event[int]) error { return nil },
+		func(int) error { return nil },
+	)
+	require.NoError(t, aggregate.ApplyChange(events.NewEvent(id, transaction.ID, 1, 1, 1, 1)))
+
+	tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateOrCreateAggregate(ctx, transaction, aggregate, 0, tx))
+	require.NoError(t, store.Commit(ctx, tx))
+}
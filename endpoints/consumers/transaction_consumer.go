@@ -3,8 +3,11 @@ package consumers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/dto"
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
@@ -17,45 +20,175 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const DefaultPollInterval = 10 * time.Second
+
+type TransactionConsumerOption func(*transactionConsumerConfig)
+
+func WithPollInterval(interval time.Duration) TransactionConsumerOption {
+	return func(cfg *transactionConsumerConfig) {
+		cfg.pollInterval = interval
+	}
+}
+
+func WithoutNotifications() TransactionConsumerOption {
+	return func(cfg *transactionConsumerConfig) {
+		cfg.notifications = false
+	}
+}
+
+type transactionConsumerConfig struct {
+	pollInterval  time.Duration
+	notifications bool
+}
+
+type TransactionConsumer interface {
+	endpoints.EndpointStarter
+	State() postgresql.ListenerState
+}
+
 type consumer struct {
 	*endpoints.Endpoint
+	listener *postgresql.Listener
+}
+
+func (c *consumer) State() postgresql.ListenerState {
+	if c.listener == nil {
+		return postgresql.ListenerState{}
+	}
+	return c.listener.State()
+}
+
+type transactionConsumer[T, S, P, K, E any] struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	handler      services.TransactionHandler[T, S, P, K, E]
+	providerFn   func(id uuid.UUID) entities.AggregateProvider[T, S, P, K]
+	listener     *postgresql.Listener
+	pollInterval time.Duration
+	wake         chan *transactions.Transaction
+	done         chan struct{}
+	log          *slog.Logger
 }
 
 func NewTransactionConsumer[T, S, P, K, E any](
 	ctx context.Context,
 	ch string,
-	conn *pgxpool.Conn,
+	subscription string,
+	acquire func(context.Context) (*pgxpool.Conn, error),
 	handler services.TransactionHandler[T, S, P, K, E],
 	providerFn func(id uuid.UUID) entities.AggregateProvider[T, S, P, K],
-) endpoints.EndpointStarter {
-	logger := slog.Default()
-	listener := postgresql.NewListener(
-		ch,
-		conn,
-		func(ctx context.Context, notification *pgconn.Notification) {
-			tx, err := convert(notification)
-			if err != nil {
-				slog.Error(err.Error())
-				return
-			}
-			err = handler.Handle(ctx, tx, providerFn)
-			if err != nil {
-				slog.Error(err.Error())
-				return
-			}
-		},
-		func() context.Context {
-			return ctx
-		},
-		logger,
-	)
+	opts ...TransactionConsumerOption,
+) TransactionConsumer {
+	cfg := &transactionConsumerConfig{pollInterval: DefaultPollInterval, notifications: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	logger := slog.Default().With(slog.String("subscription", subscription))
+	ctx, cancel := context.WithCancel(ctx)
+	tc := &transactionConsumer[T, S, P, K, E]{
+		ctx:          ctx,
+		cancel:       cancel,
+		handler:      handler,
+		providerFn:   providerFn,
+		pollInterval: cfg.pollInterval,
+		wake:         make(chan *transactions.Transaction, 1),
+		done:         make(chan struct{}),
+		log:          logger,
+	}
+	if cfg.notifications {
+		tc.listener = postgresql.NewListener(
+			ch,
+			acquire,
+			func(ctx context.Context, notification *pgconn.Notification) {
+				tx, err := convert(notification)
+				if err != nil {
+					logger.ErrorContext(ctx, err.Error())
+					return
+				}
+				tc.notify(tx)
+			},
+			func(context.Context) {
+				tc.notify(nil)
+			},
+			func() context.Context {
+				return ctx
+			},
+			logger,
+		)
+	}
 
 	return &consumer{
 		Endpoint: endpoints.NewEndpoint(
-			listener.StartListen,
-			listener.Shutdown,
+			tc.start,
+			tc.stop,
 			logger,
 		),
+		listener: tc.listener,
+	}
+}
+
+func (tc *transactionConsumer[T, S, P, K, E]) notify(tx *transactions.Transaction) {
+	select {
+	case tc.wake <- tx:
+	default:
+	}
+}
+
+func (tc *transactionConsumer[T, S, P, K, E]) start() error {
+	defer close(tc.done)
+	listenErr := make(chan error, 1)
+	if tc.listener != nil {
+		go func() {
+			listenErr <- tc.listener.StartListen()
+		}()
+	}
+	var tick <-chan time.Time
+	if tc.pollInterval > 0 {
+		ticker := time.NewTicker(tc.pollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	tc.notify(nil)
+	for {
+		select {
+		case <-tc.ctx.Done():
+			return http.ErrServerClosed
+		case err := <-listenErr:
+			if errors.Is(err, http.ErrServerClosed) {
+				continue
+			}
+			if tick == nil {
+				return err
+			}
+			tc.log.ErrorContext(tc.ctx, err.Error())
+		case tx := <-tc.wake:
+			tc.handle(tx)
+		case <-tick:
+			tc.handle(nil)
+		}
+	}
+}
+
+func (tc *transactionConsumer[T, S, P, K, E]) handle(tx *transactions.Transaction) {
+	err := tc.handler.Handle(tc.ctx, tx, tc.providerFn)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		tc.log.ErrorContext(tc.ctx, err.Error())
+	}
+}
+
+func (tc *transactionConsumer[T, S, P, K, E]) stop(ctx context.Context) error {
+	tc.cancel()
+	if tc.listener != nil {
+		if err := tc.listener.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-tc.done:
+		tc.log.InfoContext(ctx, "transaction consumer shutting down successfully")
+		return nil
 	}
 }
 
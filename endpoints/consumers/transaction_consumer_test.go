@@ -0,0 +1,136 @@
+package consumers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/endpoints/consumers"
+	"github.com/alex-fullstack/event-sourcingo/mocks/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const consumerWaitTimeout = 2 * time.Second
+
+type TransactionConsumerTestCase struct {
+	description   string
+	opts          []consumers.TransactionConsumerOption
+	expectedCalls int
+	mockAssertion func(calls chan<- struct{})
+}
+
+func TestTransactionConsumer_GracefulStartMethod(t *testing.T) {
+	var (
+		handlerMock *services.MockTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}]
+		errExpected = errors.New("test error")
+		providerFn  = func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}] {
+			return nil
+		}
+		handle = func(calls chan<- struct{}, err error) func(
+			context.Context,
+			*transactions.Transaction,
+			func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+		) error {
+			return func(
+				context.Context,
+				*transactions.Transaction,
+				func(uuid.UUID) entities.AggregateProvider[*struct{}, *struct{}, *struct{}, *struct{}],
+			) error {
+				select {
+				case calls <- struct{}{}:
+				default:
+				}
+				return err
+			}
+		}
+	)
+	testCases := []TransactionConsumerTestCase{
+		{
+			description: "При запуске потребителя должна однократно выполняться догоняющая обработка транзакций",
+			opts: []consumers.TransactionConsumerOption{
+				consumers.WithoutNotifications(),
+				consumers.WithPollInterval(time.Hour),
+			},
+			expectedCalls: 1,
+			mockAssertion: func(calls chan<- struct{}) {
+				handlerMock.EXPECT().
+					Handle(mock.Anything, (*transactions.Transaction)(nil), mock.Anything).
+					RunAndReturn(handle(calls, nil)).
+					Once()
+			},
+		},
+		{
+			description: "По истечении интервала опроса потребитель должен повторно выполнять обработку транзакций",
+			opts: []consumers.TransactionConsumerOption{
+				consumers.WithoutNotifications(),
+				consumers.WithPollInterval(10 * time.Millisecond),
+			},
+			expectedCalls: 3,
+			mockAssertion: func(calls chan<- struct{}) {
+				handlerMock.EXPECT().
+					Handle(mock.Anything, (*transactions.Transaction)(nil), mock.Anything).
+					RunAndReturn(handle(calls, nil))
+			},
+		},
+		{
+			description: "Если обработка транзакций завершилась ошибкой, то потребитель должен продолжить опрос",
+			opts: []consumers.TransactionConsumerOption{
+				consumers.WithoutNotifications(),
+				consumers.WithPollInterval(10 * time.Millisecond),
+			},
+			expectedCalls: 2,
+			mockAssertion: func(calls chan<- struct{}) {
+				handlerMock.EXPECT().
+					Handle(mock.Anything, (*transactions.Transaction)(nil), mock.Anything).
+					RunAndReturn(handle(calls, errExpected))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				handlerMock = services.NewMockTransactionHandler[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](t)
+				calls := make(chan struct{}, tc.expectedCalls)
+				tc.mockAssertion(calls)
+
+				consumer := consumers.NewTransactionConsumer[*struct{}, *struct{}, *struct{}, *struct{}, *struct{}](
+					context.Background(),
+					"transactions",
+					"test",
+					nil,
+					handlerMock,
+					providerFn,
+					tc.opts...,
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				timeout := consumerWaitTimeout
+				result := make(chan error, 1)
+				go func() {
+					result <- consumer.GracefulStart(ctx, &timeout)
+				}()
+				for range tc.expectedCalls {
+					select {
+					case <-calls:
+					case <-time.After(consumerWaitTimeout):
+						require.FailNow(t, "transaction handler was not called")
+					}
+				}
+				cancel()
+				select {
+				case err := <-result:
+					assert.NoError(t, err)
+				case <-time.After(2 * consumerWaitTimeout):
+					require.FailNow(t, "consumer did not stop after the start context was cancelled")
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,20 @@
+package streams
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/endpoints"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func NewEventStreamEndpoint[T, S, E any](addr string, stream *EventStream[T, S, E]) endpoints.EndpointStarter {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           stream,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	server.RegisterOnShutdown(stream.Close)
+	return endpoints.NewEndpoint(server.ListenAndServe, server.Shutdown, stream.log)
+}
@@ -0,0 +1,317 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/dto"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPollInterval = 500 * time.Millisecond
+	DefaultBatchSize    = 100
+	DefaultHeartbeat    = 15 * time.Second
+
+	lastEventIDHeader  = "Last-Event-ID"
+	lastEventIDParam   = "last_event_id"
+	aggregateIDParam   = "aggregate_id"
+	aggregateTypeParam = "aggregate_type"
+	eventTypeParam     = "event_type"
+)
+
+var errStreamClosed = errors.New("event stream closed")
+
+type EventStreamOption[T, S, E any] func(*EventStream[T, S, E])
+
+func WithPollInterval[T, S, E any](interval time.Duration) EventStreamOption[T, S, E] {
+	return func(s *EventStream[T, S, E]) {
+		s.interval = interval
+	}
+}
+
+func WithBatchSize[T, S, E any](batchSize int) EventStreamOption[T, S, E] {
+	return func(s *EventStream[T, S, E]) {
+		s.batchSize = batchSize
+	}
+}
+
+func WithHeartbeat[T, S, E any](heartbeat time.Duration) EventStreamOption[T, S, E] {
+	return func(s *EventStream[T, S, E]) {
+		s.heartbeat = heartbeat
+	}
+}
+
+func WithMapper[T, S, E any](mapper func(event events.StreamEvent[T]) (any, bool)) EventStreamOption[T, S, E] {
+	return func(s *EventStream[T, S, E]) {
+		s.mapper = mapper
+	}
+}
+
+func WithOriginPatterns[T, S, E any](patterns ...string) EventStreamOption[T, S, E] {
+	return func(s *EventStream[T, S, E]) {
+		s.originPatterns = patterns
+	}
+}
+
+type EventStream[T, S, E any] struct {
+	store          repositories.EventStore[T, S, E]
+	mapper         func(event events.StreamEvent[T]) (any, bool)
+	interval       time.Duration
+	batchSize      int
+	heartbeat      time.Duration
+	originPatterns []string
+	done           chan struct{}
+	closeOnce      sync.Once
+	log            *slog.Logger
+}
+
+func NewEventStream[T, S, E any](
+	store repositories.EventStore[T, S, E],
+	opts ...EventStreamOption[T, S, E],
+) *EventStream[T, S, E] {
+	s := &EventStream[T, S, E]{
+		store: store,
+		mapper: func(event events.StreamEvent[T]) (any, bool) {
+			return dto.NewStreamEvent(event), true
+		},
+		interval:  DefaultPollInterval,
+		batchSize: DefaultBatchSize,
+		heartbeat: DefaultHeartbeat,
+		done:      make(chan struct{}),
+		log:       slog.Default().With(slog.String("endpoint", "event-stream")),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *EventStream[T, S, E]) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *EventStream[T, S, E]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.ServeWebSocket(w, r)
+		return
+	}
+	s.ServeSSE(w, r)
+}
+
+func (s *EventStream[T, S, E]) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	from, filter, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err = controller.Flush(); err != nil {
+		s.log.ErrorContext(r.Context(), err.Error())
+		return
+	}
+	err = s.stream(
+		r.Context(),
+		from,
+		filter,
+		func(id string, data []byte) error {
+			if _, errWrite := fmt.Fprintf(w, "id: %s\ndata: %s\n\n", id, data); errWrite != nil {
+				return errWrite
+			}
+			return controller.Flush()
+		},
+		func() error {
+			if _, errWrite := fmt.Fprint(w, ": heartbeat\n\n"); errWrite != nil {
+				return errWrite
+			}
+			return controller.Flush()
+		},
+	)
+	if err != nil && !errors.Is(err, errStreamClosed) && r.Context().Err() == nil {
+		s.log.ErrorContext(r.Context(), err.Error())
+	}
+}
+
+func (s *EventStream[T, S, E]) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	from, filter, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: s.originPatterns})
+	if err != nil {
+		s.log.ErrorContext(r.Context(), err.Error())
+		return
+	}
+	defer conn.CloseNow() //nolint:errcheck // closing an already closed connection is not an error to report
+	ctx := conn.CloseRead(r.Context())
+	err = s.stream(
+		ctx,
+		from,
+		filter,
+		func(id string, data []byte) error {
+			message, errMarshal := json.Marshal(dto.StreamMessage{ID: id, Data: data})
+			if errMarshal != nil {
+				return errMarshal
+			}
+			return conn.Write(ctx, websocket.MessageText, message)
+		},
+		func() error {
+			pingCtx, cancel := context.WithTimeout(ctx, s.heartbeat)
+			defer cancel()
+			return conn.Ping(pingCtx)
+		},
+	)
+	switch {
+	case errors.Is(err, errStreamClosed):
+		_ = conn.Close(websocket.StatusGoingAway, "server shutting down")
+	case err != nil && ctx.Err() == nil:
+		s.log.ErrorContext(ctx, err.Error())
+		_ = conn.Close(websocket.StatusInternalError, "stream failed")
+	}
+}
+
+func (s *EventStream[T, S, E]) stream(
+	ctx context.Context,
+	from events.Position,
+	filter events.Filter,
+	send func(id string, data []byte) error,
+	heartbeat func() error,
+) error {
+	poll := time.NewTicker(s.interval)
+	defer poll.Stop()
+	ping := time.NewTicker(s.heartbeat)
+	defer ping.Stop()
+	for {
+		var err error
+		if from, err = s.flush(ctx, from, filter, send); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.done:
+			return errStreamClosed
+		case <-ping.C:
+			if err = heartbeat(); err != nil {
+				return err
+			}
+		case <-poll.C:
+		}
+	}
+}
+
+func (s *EventStream[T, S, E]) flush(
+	ctx context.Context,
+	from events.Position,
+	filter events.Filter,
+	send func(id string, data []byte) error,
+) (events.Position, error) {
+	for {
+		batch, err := s.read(ctx, from, filter)
+		if err != nil {
+			return from, err
+		}
+		for _, event := range batch {
+			message, ok := s.mapper(event)
+			if ok {
+				data, errMarshal := json.Marshal(message)
+				if errMarshal != nil {
+					return from, errMarshal
+				}
+				if err = send(event.Position.String(), data); err != nil {
+					return from, err
+				}
+			}
+			from = event.Position
+		}
+		if len(batch) < s.batchSize {
+			return from, nil
+		}
+	}
+}
+
+func (s *EventStream[T, S, E]) read(
+	ctx context.Context,
+	from events.Position,
+	filter events.Filter,
+) ([]events.StreamEvent[T], error) {
+	executor, err := s.store.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch := make([]events.StreamEvent[T], 0, s.batchSize)
+	for event, errRead := range s.store.ReadAll(ctx, from, s.batchSize, filter, executor) {
+		if errRead != nil {
+			err = errRead
+			break
+		}
+		batch = append(batch, event)
+	}
+	if rollbackErr := s.store.Rollback(ctx, executor); err == nil {
+		err = rollbackErr
+	}
+	return batch, err
+}
+
+func parseRequest(r *http.Request) (events.Position, events.Filter, error) {
+	var from events.Position
+	var filter events.Filter
+	query := r.URL.Query()
+	lastEventID := r.Header.Get(lastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = query.Get(lastEventIDParam)
+	}
+	if lastEventID != "" {
+		position, err := events.ParsePosition(lastEventID)
+		if err != nil {
+			return from, filter, err
+		}
+		from = position
+	}
+	for _, value := range values(query[aggregateIDParam]) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return from, filter, fmt.Errorf("invalid %s %q: %w", aggregateIDParam, value, err)
+		}
+		filter.AggregateIDs = append(filter.AggregateIDs, id)
+	}
+	for _, value := range values(query[eventTypeParam]) {
+		eventType, err := strconv.Atoi(value)
+		if err != nil {
+			return from, filter, fmt.Errorf("invalid %s %q: %w", eventTypeParam, value, err)
+		}
+		filter.EventTypes = append(filter.EventTypes, eventType)
+	}
+	filter.AggregateTypes = values(query[aggregateTypeParam])
+	return from, filter, nil
+}
+
+func values(params []string) []string {
+	var result []string
+	for _, param := range params {
+		for value := range strings.SplitSeq(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
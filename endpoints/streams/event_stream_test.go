@@ -0,0 +1,305 @@
+package streams
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamWaitTimeout = 2 * time.Second
+
+type ValuesTestCase struct {
+	description string
+	params      []string
+	expected    []string
+}
+
+func TestValues(t *testing.T) {
+	testCases := []ValuesTestCase{
+		{
+			description: "Значения, разделенные запятыми, должны разбиваться с обрезкой пробелов",
+			params:      []string{"a, b", " c "},
+			expected:    []string{"a", "b", "c"},
+		},
+		{
+			description: "Пустые значения должны пропускаться",
+			params:      []string{",", " , a,,"},
+			expected:    []string{"a"},
+		},
+		{
+			description: "Без параметров должен возвращаться пустой список",
+			params:      nil,
+			expected:    nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				assert.Equal(t, tc.expected, values(tc.params))
+			},
+		)
+	}
+}
+
+type ParseRequestTestCase struct {
+	description   string
+	target        string
+	lastEventID   string
+	expectedFrom  events.Position
+	expectedError bool
+	filter        func(filter events.Filter)
+}
+
+func TestParseRequest(t *testing.T) {
+	firstID, secondID := uuid.New(), uuid.New()
+	testCases := []ParseRequestTestCase{
+		{
+			description:  "Без параметров поток должен читаться с начала без фильтров",
+			target:       "/",
+			expectedFrom: events.Position{},
+			filter: func(filter events.Filter) {
+				assert.Equal(t, events.Filter{}, filter)
+			},
+		},
+		{
+			description:  "Заголовок Last-Event-ID должен иметь приоритет над параметром запроса",
+			target:       "/?last_event_id=1-1",
+			lastEventID:  "5-2",
+			expectedFrom: events.Position{SequenceID: 5, Version: 2},
+		},
+		{
+			description:  "Без заголовка позиция должна браться из параметра запроса",
+			target:       "/?last_event_id=3-4",
+			expectedFrom: events.Position{SequenceID: 3, Version: 4},
+		},
+		{
+			description: "Фильтры по агрегатам, типам агрегатов и событий должны собираться из повторяющихся и перечисленных значений", //nolint:lll
+			target: "/?aggregate_id=" + firstID.String() + "," + secondID.String() +
+				"&aggregate_type=role&aggregate_type=policy&event_type=1,2",
+			filter: func(filter events.Filter) {
+				assert.Equal(t, []uuid.UUID{firstID, secondID}, filter.AggregateIDs)
+				assert.Equal(t, []string{"role", "policy"}, filter.AggregateTypes)
+				assert.Equal(t, []int{1, 2}, filter.EventTypes)
+			},
+		},
+		{
+			description:   "Некорректная позиция должна приводить к ошибке",
+			target:        "/",
+			lastEventID:   "abc",
+			expectedError: true,
+		},
+		{
+			description:   "Некорректный идентификатор агрегата должен приводить к ошибке",
+			target:        "/?aggregate_id=abc",
+			expectedError: true,
+		},
+		{
+			description:   "Некорректный тип события должен приводить к ошибке",
+			target:        "/?event_type=abc",
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+				if tc.lastEventID != "" {
+					r.Header.Set(lastEventIDHeader, tc.lastEventID)
+				}
+				from, filter, err := parseRequest(r)
+
+				if tc.expectedError {
+					assert.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedFrom, from)
+				if tc.filter != nil {
+					tc.filter(filter)
+				}
+			},
+		)
+	}
+}
+
+type sseFrame struct {
+	id   string
+	data string
+}
+
+type EventStreamTestCase struct {
+	description    string
+	opts           []EventStreamOption[int, int, *memory.Transaction]
+	lastEventID    string
+	expectedFrames []sseFrame
+}
+
+func TestEventStream_ServeSSEMethod(t *testing.T) {
+	skipFirst := func(event events.StreamEvent[int]) (any, bool) {
+		return event.Payload, event.Position != events.Position{SequenceID: 1, Version: 1}
+	}
+	payload := func(event events.StreamEvent[int]) (any, bool) {
+		return event.Payload, true
+	}
+	testCases := []EventStreamTestCase{
+		{
+			description: "Поток должен отдавать все события в формате SSE с позицией в качестве идентификатора",
+			opts: []EventStreamOption[int, int, *memory.Transaction]{
+				WithMapper[int, int, *memory.Transaction](payload),
+			},
+			expectedFrames: []sseFrame{{"1-1", "10"}, {"1-2", "20"}, {"2-1", "30"}},
+		},
+		{
+			description: "Поток должен продолжать чтение после позиции из заголовка Last-Event-ID",
+			opts: []EventStreamOption[int, int, *memory.Transaction]{
+				WithMapper[int, int, *memory.Transaction](payload),
+			},
+			lastEventID:    "1-1",
+			expectedFrames: []sseFrame{{"1-2", "20"}, {"2-1", "30"}},
+		},
+		{
+			description: "Отброшенное преобразователем событие не должно отправляться, но должно сдвигать позицию чтения",
+			opts: []EventStreamOption[int, int, *memory.Transaction]{
+				WithMapper[int, int, *memory.Transaction](skipFirst),
+				WithBatchSize[int, int, *memory.Transaction](1),
+			},
+			expectedFrames: []sseFrame{{"1-2", "20"}, {"2-1", "30"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				store := memory.NewEventStore[int, int]()
+				writeEvents(t, store, uuid.New(), 10, 20)
+				writeEvents(t, store, uuid.New(), 30)
+				stream := NewEventStream[int, int, *memory.Transaction](store, tc.opts...)
+				server := httptest.NewServer(stream)
+				defer server.Close()
+				defer stream.Close()
+
+				body := openSSE(t, server.URL, tc.lastEventID)
+				for _, expected := range tc.expectedFrames {
+					assert.Equal(t, expected, readFrame(t, body))
+				}
+			},
+		)
+	}
+}
+
+func TestEventStream_LiveEvents(t *testing.T) {
+	store := memory.NewEventStore[int, int]()
+	stream := NewEventStream[int, int, *memory.Transaction](
+		store,
+		WithPollInterval[int, int, *memory.Transaction](10*time.Millisecond),
+		WithMapper[int, int, *memory.Transaction](func(event events.StreamEvent[int]) (any, bool) {
+			return event.Payload, true
+		}),
+	)
+	server := httptest.NewServer(stream)
+	defer server.Close()
+
+	body := openSSE(t, server.URL, "")
+	writeEvents(t, store, uuid.New(), 1)
+	assert.Equal(t, sseFrame{"1-1", "1"}, readFrame(t, body))
+
+	t.Run("После вызова Close поток должен завершать открытые соединения", func(t *testing.T) {
+		stream.Close()
+		done := make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(body)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(streamWaitTimeout):
+			require.FailNow(t, "stream was not closed")
+		}
+	})
+}
+
+func openSSE(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		r.Header.Set(lastEventIDHeader, lastEventID)
+	}
+	response, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = response.Body.Close()
+	})
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+	return bufio.NewReader(response.Body)
+}
+
+func readFrame(t *testing.T, body *bufio.Reader) sseFrame {
+	t.Helper()
+	lines := make(chan []string, 1)
+	go func() {
+		var frame []string
+		for {
+			line, err := body.ReadString('\n')
+			if err != nil {
+				lines <- append(frame, err.Error())
+				return
+			}
+			if line = strings.TrimSuffix(line, "\n"); line == "" {
+				lines <- frame
+				return
+			}
+			frame = append(frame, line)
+		}
+	}()
+	select {
+	case frame := <-lines:
+		require.Len(t, frame, 2)
+		return sseFrame{
+			id:   strings.TrimPrefix(frame[0], "id: "),
+			data: strings.TrimPrefix(frame[1], "data: "),
+		}
+	case <-time.After(streamWaitTimeout):
+		require.FailNow(t, "stream did not send an event")
+	}
+	return sseFrame{}
+}
+
+func writeEvents(t *testing.T, store *memory.EventStore[int, int], id uuid.UUID, payloads ...int) {
+	t.Helper()
+	ctx := context.Background()
+	transaction := transactions.NewTransaction(uuid.New(), id, 0)
+	aggregate := entities.NewAggregate[int, int](
+		id,
+		10,
+		func(events.Event[int]) error { return nil },
+		func(int) error { return nil },
+	)
+	for i, payload := range payloads {
+		require.NoError(t, aggregate.ApplyChange(events.NewEvent(id, transaction.ID, 1, i+1, 1, payload)))
+	}
+	tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateOrCreateAggregate(ctx, transaction, aggregate, 0, tx))
+	require.NoError(t, store.Commit(ctx, tx))
+}
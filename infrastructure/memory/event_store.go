@@ -0,0 +1,558 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+)
+
+type snapshot[S any] struct {
+	version   int
+	payload   S
+	createdAt time.Time
+}
+
+type transactionRecord struct {
+	transaction transactions.Transaction
+	createdAt   time.Time
+}
+
+type state[T, S any] struct {
+	sequence      int64
+	aggregates    map[uuid.UUID]int
+	types         map[uuid.UUID]string
+	events        map[uuid.UUID][]events.Event[T]
+	snapshots     map[uuid.UUID][]snapshot[S]
+	transactions  []transactionRecord
+	subscriptions map[string]int64
+}
+
+func newState[T, S any]() *state[T, S] {
+	return &state[T, S]{
+		aggregates:    make(map[uuid.UUID]int),
+		types:         make(map[uuid.UUID]string),
+		events:        make(map[uuid.UUID][]events.Event[T]),
+		snapshots:     make(map[uuid.UUID][]snapshot[S]),
+		subscriptions: make(map[string]int64),
+	}
+}
+
+func (s *state[T, S]) clone() *state[T, S] {
+	cloned := &state[T, S]{
+		sequence:      s.sequence,
+		aggregates:    maps.Clone(s.aggregates),
+		types:         maps.Clone(s.types),
+		events:        make(map[uuid.UUID][]events.Event[T], len(s.events)),
+		snapshots:     make(map[uuid.UUID][]snapshot[S], len(s.snapshots)),
+		transactions:  slices.Clone(s.transactions),
+		subscriptions: maps.Clone(s.subscriptions),
+	}
+	for id, stream := range s.events {
+		cloned.events[id] = slices.Clone(stream)
+	}
+	for id, snapshots := range s.snapshots {
+		cloned.snapshots[id] = slices.Clone(snapshots)
+	}
+	return cloned
+}
+
+type EventStore[T, S any] struct {
+	mu        sync.Mutex
+	committed *state[T, S]
+}
+
+func NewEventStore[T, S any]() *EventStore[T, S] {
+	return &EventStore[T, S]{committed: newState[T, S]()}
+}
+
+func (es *EventStore[T, S]) Begin(ctx context.Context) (*Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	es.mu.Lock()
+	base := es.committed
+	es.mu.Unlock()
+	return &Transaction{state: base.clone(), base: base}, nil
+}
+
+func (es *EventStore[T, S]) Commit(_ context.Context, tx *Transaction) error {
+	st, err := es.working(tx)
+	if err != nil {
+		return err
+	}
+	tx.closed = true
+	if len(tx.writes) > 0 {
+		if err = es.apply(tx, st); err != nil {
+			return err
+		}
+	}
+	for _, fn := range tx.onCommit {
+		fn()
+	}
+	return nil
+}
+
+func (es *EventStore[T, S]) Rollback(_ context.Context, tx *Transaction) error {
+	if _, err := es.working(tx); err != nil {
+		return err
+	}
+	tx.closed = true
+	return nil
+}
+
+func (es *EventStore[T, S]) apply(tx *Transaction, st *state[T, S]) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if tx.base != any(es.committed) {
+		st = es.committed.clone()
+		for _, write := range tx.writes {
+			if err := write(st); err != nil {
+				return err
+			}
+		}
+	}
+	es.committed = st
+	return nil
+}
+
+func (es *EventStore[T, S]) write(tx *Transaction, fn func(st *state[T, S]) error) error {
+	st, err := es.working(tx)
+	if err != nil {
+		return err
+	}
+	if err = fn(st); err != nil {
+		return err
+	}
+	tx.writes = append(tx.writes, func(target any) error {
+		replayed, ok := target.(*state[T, S])
+		if !ok {
+			return ErrForeignTransaction
+		}
+		return fn(replayed)
+	})
+	return nil
+}
+
+func (es *EventStore[T, S]) UpdateOrCreateAggregate(
+	_ context.Context,
+	transaction *transactions.Transaction,
+	reader entities.AggregateReader[T],
+	payload S,
+	tx *Transaction,
+) error {
+	return es.write(tx, func(st *state[T, S]) error {
+		return updateOrCreateAggregate(st, transaction, reader, payload)
+	})
+}
+
+func updateOrCreateAggregate[T, S any](
+	st *state[T, S],
+	transaction *transactions.Transaction,
+	reader entities.AggregateReader[T],
+	payload S,
+) error {
+	currentVersion, nextVersion := reader.BaseVersion(), reader.Version()
+	actualVersion, exists := st.aggregates[reader.ID()]
+	if exists != (currentVersion != 0) || actualVersion != currentVersion {
+		return repositories.NewConcurrencyConflictError(reader.ID(), currentVersion, actualVersion)
+	}
+	if transaction.CommandID != "" && slices.ContainsFunc(st.transactions, func(r transactionRecord) bool {
+		return r.transaction.CommandID == transaction.CommandID
+	}) {
+		return repositories.ErrDuplicateCommand
+	}
+	st.aggregates[reader.ID()] = nextVersion
+	if st.types[reader.ID()] == "" {
+		st.types[reader.ID()] = reader.Type()
+	}
+	now := time.Now()
+	if nextVersion/reader.Cap() > currentVersion/reader.Cap() {
+		st.snapshots[reader.ID()] = append(st.snapshots[reader.ID()], snapshot[S]{
+			version:   nextVersion,
+			payload:   payload,
+			createdAt: now,
+		})
+	}
+	for _, event := range reader.Changes() {
+		event.CreatedAt = &now
+		st.events[event.AggregateID] = append(st.events[event.AggregateID], event)
+	}
+	st.sequence++
+	record := transactionRecord{transaction: *transaction, createdAt: now}
+	record.transaction.SequenceID = st.sequence
+	st.transactions = append(st.transactions, record)
+	return nil
+}
+
+func (es *EventStore[T, S]) GetSnapshot(
+	_ context.Context,
+	id uuid.UUID,
+	versionAfter *int,
+	tx *Transaction,
+) (int, S, error) {
+	var payload S
+	st, err := es.working(tx)
+	if err != nil {
+		return 0, payload, err
+	}
+	snapshots := st.snapshots[id]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if versionAfter == nil || snapshots[i].version < *versionAfter {
+			return snapshots[i].version, snapshots[i].payload, nil
+		}
+	}
+	return 0, payload, nil
+}
+
+func (es *EventStore[T, S]) GetSnapshotAsOf(
+	_ context.Context,
+	id uuid.UUID,
+	asOf time.Time,
+	tx *Transaction,
+) (int, S, error) {
+	var payload S
+	st, err := es.working(tx)
+	if err != nil {
+		return 0, payload, err
+	}
+	snapshots := st.snapshots[id]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].createdAt.After(asOf) {
+			return snapshots[i].version, snapshots[i].payload, nil
+		}
+	}
+	return 0, payload, nil
+}
+
+func (es *EventStore[T, S]) GetEvents(
+	_ context.Context,
+	id uuid.UUID,
+	fromVersion int,
+	toVersion *int,
+	tx *Transaction,
+) ([]events.Event[T], error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]events.Event[T], 0)
+	for _, event := range st.events[id] {
+		if event.Version >= fromVersion && (toVersion == nil || event.Version <= *toVersion) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) GetEventsUntil(
+	_ context.Context,
+	id uuid.UUID,
+	fromVersion int,
+	until time.Time,
+	tx *Transaction,
+) ([]events.Event[T], error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]events.Event[T], 0)
+	for _, event := range st.events[id] {
+		if event.Version >= fromVersion && !event.CreatedAt.After(until) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) GetUnhandledEvents(
+	_ context.Context,
+	id uuid.UUID,
+	firstSequenceID, lastSequenceID int64,
+	tx *Transaction,
+) ([]events.Event[T], error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	var result []events.Event[T]
+	for _, record := range st.transactions {
+		if record.transaction.AggregateID != id ||
+			record.transaction.SequenceID <= firstSequenceID ||
+			record.transaction.SequenceID > lastSequenceID {
+			continue
+		}
+		for _, event := range st.events[id] {
+			if event.TransactionID == record.transaction.ID {
+				result = append(result, event)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) ReadAll(
+	_ context.Context,
+	fromPosition events.Position,
+	limit int,
+	filter events.Filter,
+	tx *Transaction,
+) iter.Seq2[events.StreamEvent[T], error] {
+	return repositories.ReadPages(
+		fromPosition,
+		limit,
+		func(after events.Position, pageSize int) ([]events.StreamEvent[T], error) {
+			st, err := es.working(tx)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]events.StreamEvent[T], 0, pageSize)
+			for _, record := range st.transactions {
+				if record.transaction.SequenceID < after.SequenceID {
+					continue
+				}
+				aggregateType := st.types[record.transaction.AggregateID]
+				for _, event := range st.events[record.transaction.AggregateID] {
+					position := events.Position{SequenceID: record.transaction.SequenceID, Version: event.Version}
+					if event.TransactionID != record.transaction.ID ||
+						!position.After(after) ||
+						!filter.Matches(event.AggregateID, event.Type, event.CommandType, aggregateType) {
+						continue
+					}
+					result = append(result, events.StreamEvent[T]{
+						Event:         event,
+						Position:      position,
+						AggregateType: aggregateType,
+					})
+					if len(result) == pageSize {
+						return result, nil
+					}
+				}
+			}
+			return result, nil
+		},
+	)
+}
+
+func (es *EventStore[T, S]) GetTransactions(
+	_ context.Context,
+	afterSequenceID int64,
+	limit int,
+	tx *Transaction,
+) ([]*transactions.Transaction, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*transactions.Transaction, 0, limit)
+	for _, record := range st.transactions {
+		if len(result) == limit {
+			break
+		}
+		if record.transaction.SequenceID > afterSequenceID {
+			transaction := record.transaction
+			transaction.AggregateType = st.types[transaction.AggregateID]
+			result = append(result, &transaction)
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) GetAggregateIDs(
+	_ context.Context,
+	afterID uuid.UUID,
+	limit int,
+	aggregateType string,
+	tx *Transaction,
+) ([]uuid.UUID, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	ids := slices.SortedFunc(maps.Keys(st.aggregates), func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	start, found := slices.BinarySearchFunc(ids, afterID, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	if found {
+		start++
+	}
+	result := make([]uuid.UUID, 0, limit)
+	for _, id := range ids[start:] {
+		if len(result) == limit {
+			break
+		}
+		if aggregateType == "" || st.types[id] == aggregateType {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) GetAggregateTypes(
+	_ context.Context,
+	ids []uuid.UUID,
+	tx *Transaction,
+) (map[uuid.UUID]string, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]string, len(ids))
+	for _, id := range ids {
+		if _, ok := st.aggregates[id]; ok {
+			result[id] = st.types[id]
+		}
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) CountTransactions(
+	_ context.Context,
+	afterSequenceID int64,
+	tx *Transaction,
+) (int64, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, record := range st.transactions {
+		if record.transaction.SequenceID > afterSequenceID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (es *EventStore[T, S]) HasCommand(
+	_ context.Context,
+	commandID string,
+	since time.Time,
+	tx *Transaction,
+) (bool, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(st.transactions, func(record transactionRecord) bool {
+		return record.transaction.CommandID == commandID && !record.createdAt.Before(since)
+	}), nil
+}
+
+func (es *EventStore[T, S]) PurgeCommands(
+	_ context.Context,
+	before time.Time,
+	tx *Transaction,
+) (int64, error) {
+	var purged int64
+	err := es.write(tx, func(st *state[T, S]) error {
+		purged = 0
+		for i, record := range st.transactions {
+			if record.transaction.CommandID != "" && record.createdAt.Before(before) {
+				st.transactions[i].transaction.CommandID = ""
+				purged++
+			}
+		}
+		return nil
+	})
+	return purged, err
+}
+
+func (es *EventStore[T, S]) GetSubscription(
+	_ context.Context,
+	name string,
+	tx *Transaction,
+) (*subscriptions.Subscription, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	if lastSequenceID, ok := st.subscriptions[name]; ok {
+		return subscriptions.NewSubscription(name, lastSequenceID), nil
+	}
+	err = es.write(tx, func(st *state[T, S]) error {
+		if _, ok := st.subscriptions[name]; !ok {
+			st.subscriptions[name] = 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions.NewSubscription(name, st.subscriptions[name]), nil
+}
+
+func (es *EventStore[T, S]) GetSubscriptions(
+	_ context.Context,
+	tx *Transaction,
+) ([]*subscriptions.Subscription, error) {
+	st, err := es.working(tx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*subscriptions.Subscription, 0, len(st.subscriptions))
+	for _, name := range slices.Sorted(maps.Keys(st.subscriptions)) {
+		result = append(result, subscriptions.NewSubscription(name, st.subscriptions[name]))
+	}
+	return result, nil
+}
+
+func (es *EventStore[T, S]) UpdateSubscription(
+	_ context.Context,
+	sub *subscriptions.Subscription,
+	tx *Transaction,
+) error {
+	st, err := es.working(tx)
+	if err != nil {
+		return err
+	}
+	seen := st.subscriptions[sub.Name]
+	return es.write(tx, func(st *state[T, S]) error {
+		if st.subscriptions[sub.Name] != seen {
+			return fmt.Errorf(
+				"%w: subscription %s moved to %d",
+				repositories.ErrConcurrencyConflict,
+				sub.Name,
+				st.subscriptions[sub.Name],
+			)
+		}
+		st.subscriptions[sub.Name] = sub.LastSequenceID
+		return nil
+	})
+}
+
+func (es *EventStore[T, S]) ResetSubscription(
+	_ context.Context,
+	name string,
+	lastSequenceID int64,
+	tx *Transaction,
+) error {
+	return es.write(tx, func(st *state[T, S]) error {
+		st.subscriptions[name] = lastSequenceID
+		return nil
+	})
+}
+
+func (es *EventStore[T, S]) working(tx *Transaction) (*state[T, S], error) {
+	if tx.closed {
+		return nil, ErrTransactionClosed
+	}
+	st, ok := tx.state.(*state[T, S])
+	if !ok {
+		return nil, ErrForeignTransaction
+	}
+	return st, nil
+}
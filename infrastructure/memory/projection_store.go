@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+)
+
+type projectionRecord[P any] struct {
+	version    int
+	projection P
+}
+
+type ProjectionStore[P any] struct {
+	mu          sync.RWMutex
+	projections map[uuid.UUID]projectionRecord[P]
+	key         func(projection P) (uuid.UUID, int)
+}
+
+func NewProjectionStore[P any](key func(projection P) (uuid.UUID, int)) *ProjectionStore[P] {
+	return &ProjectionStore[P]{
+		projections: make(map[uuid.UUID]projectionRecord[P]),
+		key:         key,
+	}
+}
+
+func (ps *ProjectionStore[P]) Transactional() *TransactionalProjectionStore[P] {
+	return &TransactionalProjectionStore[P]{store: ps}
+}
+
+func (ps *ProjectionStore[P]) Save(_ context.Context, projection P) error {
+	id, version := ps.key(projection)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if current, ok := ps.projections[id]; ok && current.version > version {
+		return nil
+	}
+	ps.projections[id] = projectionRecord[P]{version: version, projection: projection}
+	return nil
+}
+
+func (ps *ProjectionStore[P]) Get(_ context.Context, id uuid.UUID) (P, error) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	record, ok := ps.projections[id]
+	if !ok {
+		return record.projection, repositories.ErrProjectionNotFound
+	}
+	return record.projection, nil
+}
+
+func (ps *ProjectionStore[P]) Delete(_ context.Context, id uuid.UUID) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	delete(ps.projections, id)
+	return nil
+}
+
+func (ps *ProjectionStore[P]) Len() int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return len(ps.projections)
+}
+
+type TransactionalProjectionStore[P any] struct {
+	store *ProjectionStore[P]
+}
+
+func (ps *TransactionalProjectionStore[P]) Save(ctx context.Context, projection P, tx *Transaction) error {
+	return tx.AfterCommit(func() {
+		_ = ps.store.Save(ctx, projection)
+	})
+}
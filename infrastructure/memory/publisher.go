@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+)
+
+type Publisher[K any] struct {
+	mu        sync.Mutex
+	published []events.IntegrationEvent[K]
+}
+
+func NewPublisher[K any]() *Publisher[K] {
+	return &Publisher[K]{}
+}
+
+func (p *Publisher[K]) Publish(_ context.Context, integrationEvents []events.IntegrationEvent[K]) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, integrationEvents...)
+	return nil
+}
+
+func (p *Publisher[K]) Published() []events.IntegrationEvent[K] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.published)
+}
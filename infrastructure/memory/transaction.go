@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"errors"
+)
+
+var (
+	ErrTransactionClosed  = errors.New("transaction is already closed")
+	ErrForeignTransaction = errors.New("transaction belongs to another store")
+)
+
+type Transaction struct {
+	state    any
+	base     any
+	writes   []func(state any) error
+	onCommit []func()
+	closed   bool
+}
+
+func (tx *Transaction) AfterCommit(fn func()) error {
+	if tx.closed {
+		return ErrTransactionClosed
+	}
+	tx.onCommit = append(tx.onCommit, fn)
+	return nil
+}
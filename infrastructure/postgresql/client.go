@@ -3,27 +3,39 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/alex-fullstack/event-sourcingo/domain/entities"
 	"github.com/alex-fullstack/event-sourcingo/domain/events"
 	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const uniqueViolationCode = "23505"
+
 type Transaction pgx.Tx
 
 type PostgresDB[T, S any] struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	tables *tables
 }
 
 func NewPostgresDB[T, S any](
 	ctx context.Context,
 	cfg *pgxpool.Config,
+	opts ...PostgresDBOption,
 ) (*PostgresDB[T, S], error) {
+	options := Options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, err
@@ -32,10 +44,23 @@ func NewPostgresDB[T, S any](
 		return nil, err
 	}
 	return &PostgresDB[T, S]{
-		pool: pool,
+		pool:   pool,
+		tables: newTables(options),
 	}, nil
 }
 
+func (db *PostgresDB[T, S]) Pool() *pgxpool.Pool {
+	return db.pool
+}
+
+func (db *PostgresDB[T, S]) Options() Options {
+	return db.tables.opts
+}
+
+func (db *PostgresDB[T, S]) Channel() string {
+	return db.tables.opts.Channel
+}
+
 func (db *PostgresDB[T, S]) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
 	return db.pool.Acquire(ctx)
 }
@@ -58,42 +83,32 @@ func (db *PostgresDB[T, S]) GetSnapshot(
 	versionAfter *int,
 	tx Transaction,
 ) (int, S, error) {
-	query := `SELECT version, payload FROM es.snapshots WHERE aggregate_id = @id ORDER BY version DESC LIMIT 1`
+	query := db.tables.sql(`SELECT version, payload FROM {snapshots} WHERE aggregate_id = @id ORDER BY version DESC LIMIT 1`) //nolint:lll
 	args := pgx.NamedArgs{
 		"id": id,
 	}
 	if versionAfter != nil {
-		query = `SELECT version, payload FROM es.snapshots WHERE aggregate_id = @id AND version < @versionAfter ORDER BY version DESC LIMIT 1` //nolint:lll
+		query = db.tables.sql(`SELECT version, payload FROM {snapshots} WHERE aggregate_id = @id AND version < @versionAfter ORDER BY version DESC LIMIT 1`) //nolint:lll
 		args = pgx.NamedArgs{
 			"id":           id,
 			"versionAfter": *versionAfter,
 		}
 	}
-	var payload S
-	var version int
-	rows, err := tx.Query(ctx, query, args)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return version, payload, nil
-		}
-		return version, payload, err
-	}
-	defer rows.Close()
+	return querySnapshot[S](ctx, tx, query, args)
+}
 
-	for rows.Next() {
-		err = rows.Scan(
-			&version,
-			&payload,
-		)
-		if err != nil {
-			return version, payload, err
-		}
-	}
-	if err = rows.Err(); err != nil {
-		return version, payload, err
+func (db *PostgresDB[T, S]) GetSnapshotAsOf(
+	ctx context.Context,
+	id uuid.UUID,
+	asOf time.Time,
+	tx Transaction,
+) (int, S, error) {
+	query := db.tables.sql(`SELECT s.version, s.payload FROM {snapshots} AS s JOIN {events} AS e ON e.aggregate_id = s.aggregate_id AND e.version = s.version WHERE s.aggregate_id = @id AND e.created_at <= @asOf::timestamptz ORDER BY s.version DESC LIMIT 1`) //nolint:lll
+	args := pgx.NamedArgs{
+		"id":   id,
+		"asOf": asOf,
 	}
-
-	return version, payload, nil
+	return querySnapshot[S](ctx, tx, query, args)
 }
 
 func (db *PostgresDB[T, S]) GetEvents(
@@ -103,63 +118,36 @@ func (db *PostgresDB[T, S]) GetEvents(
 	toVersion *int,
 	tx Transaction,
 ) ([]events.Event[T], error) {
-	query := `SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, created_at FROM es.events WHERE aggregate_id = @id AND version >= @fromVersion` //nolint:lll
+	query := db.tables.sql(`SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM {events} WHERE aggregate_id = @id AND version >= @fromVersion ORDER BY version`) //nolint:lll
 	args := pgx.NamedArgs{
 		"id":          id,
 		"fromVersion": fromVersion,
 	}
 	if toVersion != nil {
-		query = `SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, created_at FROM es.events WHERE aggregate_id = @id AND version >= @fromVersion AND version <= @toVersion` //nolint:lll
+		query = db.tables.sql(`SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM {events} WHERE aggregate_id = @id AND version >= @fromVersion AND version <= @toVersion ORDER BY version`) //nolint:lll
 		args = pgx.NamedArgs{
 			"id":          id,
 			"fromVersion": fromVersion,
 			"toVersion":   *toVersion,
 		}
 	}
-	rows, err := tx.Query(ctx, query, args)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	result := make([]events.Event[T], 0)
-	for rows.Next() {
-		var aggregateID, transactionID uuid.UUID
-		var eventType, version, commandType int
-		var payload T
-		var createdAt time.Time
-
-		err = rows.Scan(
-			&aggregateID,
-			&transactionID,
-			&version,
-			&commandType,
-			&eventType,
-			&payload,
-			&createdAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+	return queryEvents[T](ctx, tx, query, args)
+}
 
-		result = append(
-			result,
-			events.Event[T]{
-				TransactionID: transactionID,
-				AggregateID:   aggregateID,
-				CommandType:   commandType,
-				Type:          eventType,
-				Version:       version,
-				Payload:       payload,
-				CreatedAt:     &createdAt,
-			},
-		)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+func (db *PostgresDB[T, S]) GetEventsUntil(
+	ctx context.Context,
+	id uuid.UUID,
+	fromVersion int,
+	until time.Time,
+	tx Transaction,
+) ([]events.Event[T], error) {
+	query := db.tables.sql(`SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM {events} WHERE aggregate_id = @id AND version >= @fromVersion AND created_at <= @until::timestamptz ORDER BY version`) //nolint:lll
+	args := pgx.NamedArgs{
+		"id":          id,
+		"fromVersion": fromVersion,
+		"until":       until,
 	}
-
-	return result, nil
+	return queryEvents[T](ctx, tx, query, args)
 }
 
 func (db *PostgresDB[T, S]) GetUnhandledEvents(
@@ -168,7 +156,7 @@ func (db *PostgresDB[T, S]) GetUnhandledEvents(
 	firstSequenceID, lastSequenceID int64,
 	tx Transaction,
 ) ([]events.Event[T], error) {
-	query := `SELECT sequence_id::text, e.aggregate_id, transaction_id, version, command_type, event_type, payload, created_at FROM es.transactions AS t JOIN es.events AS e ON e.transaction_id=t.id WHERE sequence_id > @firstSequenceId AND sequence_id <= @lastSequenceId::xid8 AND t.aggregate_id=@aggregateId ORDER BY sequence_id` //nolint:lll
+	query := db.tables.sql(`SELECT sequence_id::text, e.aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, e.created_at FROM {transactions} AS t JOIN {events} AS e ON e.transaction_id=t.id WHERE sequence_id > @firstSequenceId AND sequence_id <= @lastSequenceId::xid8 AND t.aggregate_id=@aggregateId ORDER BY sequence_id, version`) //nolint:lll
 	args := pgx.NamedArgs{
 		"firstSequenceId": firstSequenceID,
 		"lastSequenceId":  lastSequenceID,
@@ -186,6 +174,7 @@ func (db *PostgresDB[T, S]) GetUnhandledEvents(
 		var aggregateID, transactionID uuid.UUID
 		var eventType, version, commandType int
 		var payload T
+		var metadata events.Metadata
 		var createdAt time.Time
 
 		err = rows.Scan(
@@ -196,6 +185,7 @@ func (db *PostgresDB[T, S]) GetUnhandledEvents(
 			&commandType,
 			&eventType,
 			&payload,
+			&metadata,
 			&createdAt,
 		)
 		if err != nil {
@@ -206,6 +196,7 @@ func (db *PostgresDB[T, S]) GetUnhandledEvents(
 			return []events.Event[T]{}, errParse
 		}
 		event := events.Event[T]{
+			Metadata:      metadata,
 			TransactionID: transactionID,
 			AggregateID:   aggregateID,
 			CommandType:   commandType,
@@ -224,7 +215,7 @@ func (db *PostgresDB[T, S]) GetUnhandledEvents(
 
 func (db *PostgresDB[T, S]) UpdateOrCreateAggregate(
 	ctx context.Context,
-	transactionID uuid.UUID,
+	transaction *transactions.Transaction,
 	reader entities.AggregateReader[T],
 	snapshot S,
 	tx Transaction,
@@ -232,9 +223,9 @@ func (db *PostgresDB[T, S]) UpdateOrCreateAggregate(
 	currentVersion, nextVersion := reader.BaseVersion(), reader.Version()
 	var err error
 	if currentVersion == 0 {
-		err = db.createVersion(ctx, reader.ID(), nextVersion, tx)
+		err = db.createVersion(ctx, reader.ID(), reader.Type(), nextVersion, tx)
 	} else {
-		err = db.updateVersion(ctx, reader.ID(), currentVersion, nextVersion, tx)
+		err = db.updateVersion(ctx, reader.ID(), reader.Type(), currentVersion, nextVersion, tx)
 	}
 	if err != nil {
 		return err
@@ -251,26 +242,178 @@ func (db *PostgresDB[T, S]) UpdateOrCreateAggregate(
 	if err != nil {
 		return err
 	}
-	return db.insertTransaction(
-		ctx,
-		transactionID,
-		reader.ID(),
-		tx,
-	)
+	return db.insertTransaction(ctx, transaction, tx)
 }
 
 func (db *PostgresDB[T, S]) Close() {
 	db.pool.Close()
 }
 
+func (db *PostgresDB[T, S]) GetTransactions(
+	ctx context.Context,
+	afterSequenceID int64,
+	limit int,
+	tx Transaction,
+) ([]*transactions.Transaction, error) {
+	query := db.tables.sql(`SELECT t.id, t.aggregate_id, COALESCE(a.type, ''), t.sequence_id::text, COALESCE(t.command_id, '') FROM {transactions} AS t LEFT JOIN {aggregates} AS a ON a.id = t.aggregate_id WHERE t.sequence_id > @afterSequenceId::xid8 AND t.sequence_id < pg_snapshot_xmin(pg_current_snapshot()) ORDER BY t.sequence_id LIMIT @limit`) //nolint:lll
+	args := pgx.NamedArgs{
+		"afterSequenceId": afterSequenceID,
+		"limit":           limit,
+	}
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*transactions.Transaction, 0, limit)
+	for rows.Next() {
+		var id, aggregateID uuid.UUID
+		var aggregateType, sequenceID, commandID string
+		err = rows.Scan(&id, &aggregateID, &aggregateType, &sequenceID, &commandID)
+		if err != nil {
+			return nil, err
+		}
+		parsedSequenceID, errParse := strconv.ParseInt(sequenceID, 10, 64)
+		if errParse != nil {
+			return nil, errParse
+		}
+		transaction := transactions.NewTransaction(id, aggregateID, parsedSequenceID)
+		transaction.AggregateType = aggregateType
+		transaction.CommandID = commandID
+		result = append(result, transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *PostgresDB[T, S]) GetAggregateIDs(
+	ctx context.Context,
+	afterID uuid.UUID,
+	limit int,
+	aggregateType string,
+	tx Transaction,
+) ([]uuid.UUID, error) {
+	query := db.tables.sql(`SELECT id FROM {aggregates} WHERE id > @afterId AND (@aggregateType::text = '' OR type = @aggregateType) ORDER BY id LIMIT @limit`) //nolint:lll
+	args := pgx.NamedArgs{
+		"afterId":       afterID,
+		"aggregateType": aggregateType,
+		"limit":         limit,
+	}
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *PostgresDB[T, S]) GetAggregateTypes(
+	ctx context.Context,
+	ids []uuid.UUID,
+	tx Transaction,
+) (map[uuid.UUID]string, error) {
+	query := db.tables.sql(`SELECT id, COALESCE(type, '') FROM {aggregates} WHERE id = ANY(@ids)`)
+	rows, err := tx.Query(ctx, query, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]string, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		var aggregateType string
+		if err = rows.Scan(&id, &aggregateType); err != nil {
+			return nil, err
+		}
+		result[id] = aggregateType
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *PostgresDB[T, S]) CountTransactions(
+	ctx context.Context,
+	afterSequenceID int64,
+	tx Transaction,
+) (int64, error) {
+	query := db.tables.sql(`SELECT count(*) FROM {transactions} WHERE sequence_id > @afterSequenceId::xid8 AND sequence_id < pg_snapshot_xmin(pg_current_snapshot())`) //nolint:lll
+	args := pgx.NamedArgs{
+		"afterSequenceId": afterSequenceID,
+	}
+	var count int64
+	err := tx.QueryRow(ctx, query, args).Scan(&count)
+	return count, err
+}
+
+func (db *PostgresDB[T, S]) HasCommand(
+	ctx context.Context,
+	commandID string,
+	since time.Time,
+	tx Transaction,
+) (bool, error) {
+	query := db.tables.sql(`SELECT EXISTS (SELECT 1 FROM {transactions} WHERE command_id = @commandId AND created_at >= @since)`) //nolint:lll
+	args := pgx.NamedArgs{
+		"commandId": commandID,
+		"since":     since,
+	}
+	var exists bool
+	err := tx.QueryRow(ctx, query, args).Scan(&exists)
+	return exists, err
+}
+
+func (db *PostgresDB[T, S]) PurgeCommands(
+	ctx context.Context,
+	before time.Time,
+	tx Transaction,
+) (int64, error) {
+	query := db.tables.sql(`UPDATE {transactions} SET command_id = NULL WHERE command_id IS NOT NULL AND created_at < @before`) //nolint:lll
+	args := pgx.NamedArgs{
+		"before": before,
+	}
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (db *PostgresDB[T, S]) GetSubscription(
 	ctx context.Context,
+	name string,
 	tx Transaction,
 ) (*subscriptions.Subscription, error) {
-	query := `SELECT last_sequence_id::text FROM es.subscription WHERE id = 1 FOR UPDATE SKIP LOCKED`
-
+	insertQuery := db.tables.sql(`INSERT INTO {subscriptions} (name, last_sequence_id) VALUES (@name, '0'::xid8) ON CONFLICT (name) DO NOTHING`) //nolint:lll
+	query := db.tables.sql(`SELECT last_sequence_id::text FROM {subscriptions} WHERE name = @name FOR UPDATE SKIP LOCKED`)
+	args := pgx.NamedArgs{
+		"name": name,
+	}
+	_, err := tx.Exec(ctx, insertQuery, args)
+	if err != nil {
+		return nil, err
+	}
 	var lastSequenceID string
-	err := tx.QueryRow(ctx, query).Scan(&lastSequenceID)
+	err = tx.QueryRow(ctx, query, args).Scan(&lastSequenceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", repositories.ErrSubscriptionLocked, name)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -278,7 +421,37 @@ func (db *PostgresDB[T, S]) GetSubscription(
 	if err != nil {
 		return nil, err
 	}
-	return &subscriptions.Subscription{LastSequenceID: sequenceID}, nil
+	return subscriptions.NewSubscription(name, sequenceID), nil
+}
+
+func (db *PostgresDB[T, S]) GetSubscriptions(
+	ctx context.Context,
+	tx Transaction,
+) ([]*subscriptions.Subscription, error) {
+	query := db.tables.sql(`SELECT name, last_sequence_id::text FROM {subscriptions} ORDER BY name`)
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*subscriptions.Subscription, 0)
+	for rows.Next() {
+		var name, lastSequenceID string
+		err = rows.Scan(&name, &lastSequenceID)
+		if err != nil {
+			return nil, err
+		}
+		sequenceID, errParse := strconv.ParseInt(lastSequenceID, 10, 64)
+		if errParse != nil {
+			return nil, errParse
+		}
+		result = append(result, subscriptions.NewSubscription(name, sequenceID))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func (db *PostgresDB[T, S]) UpdateSubscription(
@@ -286,43 +459,93 @@ func (db *PostgresDB[T, S]) UpdateSubscription(
 	sub *subscriptions.Subscription,
 	tx Transaction,
 ) error {
-	query := `UPDATE es.subscription SET last_sequence_id = @lastSequenceId::xid8  WHERE id = 1`
+	query := db.tables.sql(`UPDATE {subscriptions} SET last_sequence_id = @lastSequenceId::xid8, updated_at = now() WHERE name = @name`) //nolint:lll
 	args := pgx.NamedArgs{
+		"name":           sub.Name,
 		"lastSequenceId": sub.LastSequenceID,
 	}
 	_, err := tx.Exec(ctx, query, args)
 	return err
 }
 
+func (db *PostgresDB[T, S]) ResetSubscription(
+	ctx context.Context,
+	name string,
+	lastSequenceID int64,
+	tx Transaction,
+) error {
+	query := db.tables.sql(`INSERT INTO {subscriptions} (name, last_sequence_id) VALUES (@name, @lastSequenceId::xid8) ON CONFLICT (name) DO UPDATE SET last_sequence_id = EXCLUDED.last_sequence_id, updated_at = now()`) //nolint:lll
+	args := pgx.NamedArgs{
+		"name":           name,
+		"lastSequenceId": lastSequenceID,
+	}
+	_, err := tx.Exec(ctx, query, args)
+	return err
+}
+
 func (db *PostgresDB[T, S]) createVersion(
 	ctx context.Context,
 	id uuid.UUID,
+	aggregateType string,
 	version int,
 	tx Transaction,
 ) error {
-	query := `INSERT INTO es.aggregates (id, version) VALUES (@id, @version)`
+	query := db.tables.sql(`INSERT INTO {aggregates} (id, version, type) VALUES (@id, @version, @type) ON CONFLICT (id) DO NOTHING`) //nolint:lll
 	args := pgx.NamedArgs{
 		"id":      id,
 		"version": version,
+		"type":    aggregateType,
 	}
-	_, err := tx.Exec(ctx, query, args)
-	return err
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.concurrencyConflict(ctx, id, 0, tx)
+	}
+	return nil
 }
 
 func (db *PostgresDB[T, S]) updateVersion(
 	ctx context.Context,
 	id uuid.UUID,
+	aggregateType string,
 	currentVersion, nextVersion int,
 	tx Transaction,
 ) error {
-	query := `UPDATE es.aggregates SET version = @nextVersion WHERE id = @id AND version = @currentVersion`
+	query := db.tables.sql(`UPDATE {aggregates} SET version = @nextVersion, type = COALESCE(NULLIF(type, ''), @type) WHERE id = @id AND version = @currentVersion`) //nolint:lll
 	args := pgx.NamedArgs{
 		"id":             id,
 		"currentVersion": currentVersion,
 		"nextVersion":    nextVersion,
+		"type":           aggregateType,
 	}
-	_, err := tx.Exec(ctx, query, args)
-	return err
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.concurrencyConflict(ctx, id, currentVersion, tx)
+	}
+	return nil
+}
+
+func (db *PostgresDB[T, S]) concurrencyConflict(
+	ctx context.Context,
+	id uuid.UUID,
+	expectedVersion int,
+	tx Transaction,
+) error {
+	query := db.tables.sql(`SELECT version FROM {aggregates} WHERE id = @id`)
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	var actualVersion int
+	err := tx.QueryRow(ctx, query, args).Scan(&actualVersion)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return repositories.NewConcurrencyConflictError(id, expectedVersion, actualVersion)
 }
 
 func (db *PostgresDB[T, S]) insertEvents(
@@ -330,7 +553,7 @@ func (db *PostgresDB[T, S]) insertEvents(
 	events []events.Event[T],
 	tx Transaction,
 ) (err error) {
-	query := `INSERT INTO es.events (aggregate_id, transaction_id, version, command_type, event_type, payload) VALUES (@aggregateId, @transactionId, @version, @commandType, @eventType, @payload)` //nolint:lll
+	query := db.tables.sql(`INSERT INTO {events} (aggregate_id, transaction_id, version, command_type, event_type, payload, metadata) VALUES (@aggregateId, @transactionId, @version, @commandType, @eventType, @payload, @metadata)`) //nolint:lll
 
 	batch := &pgx.Batch{}
 	for _, event := range events {
@@ -341,6 +564,7 @@ func (db *PostgresDB[T, S]) insertEvents(
 			"eventType":     event.Type,
 			"commandType":   event.CommandType,
 			"payload":       event.Payload,
+			"metadata":      event.Metadata,
 		}
 		batch.Queue(query, args)
 	}
@@ -364,15 +588,22 @@ func (db *PostgresDB[T, S]) insertEvents(
 
 func (db *PostgresDB[T, S]) insertTransaction(
 	ctx context.Context,
-	id, aggregateID uuid.UUID,
+	transaction *transactions.Transaction,
 	tx Transaction,
 ) error {
-	query := `INSERT INTO es.transactions (id, aggregate_id) VALUES (@id, @aggregateId)`
+	query := db.tables.sql(`INSERT INTO {transactions} (id, aggregate_id, command_id) VALUES (@id, @aggregateId, NULLIF(@commandId, ''))`) //nolint:lll
 	args := pgx.NamedArgs{
-		"id":          id,
-		"aggregateId": aggregateID,
+		"id":          transaction.ID,
+		"aggregateId": transaction.AggregateID,
+		"commandId":   transaction.CommandID,
 	}
 	_, err := tx.Exec(ctx, query, args)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) &&
+		pgErr.Code == uniqueViolationCode &&
+		pgErr.ConstraintName == db.tables.commandIDIndex() {
+		return repositories.ErrDuplicateCommand
+	}
 	return err
 }
 
@@ -383,7 +614,7 @@ func (db *PostgresDB[T, S]) insertSnapshot(
 	payload S,
 	tx Transaction,
 ) error {
-	query := `INSERT INTO es.snapshots (aggregate_id, version, payload) VALUES (@aggregateId, @version, @payload)`
+	query := db.tables.sql(`INSERT INTO {snapshots} (aggregate_id, version, payload) VALUES (@aggregateId, @version, @payload)`) //nolint:lll
 	args := pgx.NamedArgs{
 		"aggregateId": aggregateID,
 		"version":     version,
@@ -392,3 +623,86 @@ func (db *PostgresDB[T, S]) insertSnapshot(
 	_, err := tx.Exec(ctx, query, args)
 	return err
 }
+
+func querySnapshot[S any](ctx context.Context, tx Transaction, query string, args pgx.NamedArgs) (int, S, error) {
+	var payload S
+	var version int
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return version, payload, nil
+		}
+		return version, payload, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(
+			&version,
+			&payload,
+		)
+		if err != nil {
+			return version, payload, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return version, payload, err
+	}
+
+	return version, payload, nil
+}
+
+func queryEvents[T any](
+	ctx context.Context,
+	tx Transaction,
+	query string,
+	args pgx.NamedArgs,
+) ([]events.Event[T], error) {
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]events.Event[T], 0)
+	for rows.Next() {
+		var aggregateID, transactionID uuid.UUID
+		var eventType, version, commandType int
+		var payload T
+		var metadata events.Metadata
+		var createdAt time.Time
+
+		err = rows.Scan(
+			&aggregateID,
+			&transactionID,
+			&version,
+			&commandType,
+			&eventType,
+			&payload,
+			&metadata,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(
+			result,
+			events.Event[T]{
+				Metadata:      metadata,
+				TransactionID: transactionID,
+				AggregateID:   aggregateID,
+				CommandType:   commandType,
+				Type:          eventType,
+				Version:       version,
+				Payload:       payload,
+				CreatedAt:     &createdAt,
+			},
+		)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
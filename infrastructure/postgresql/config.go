@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	DefaultSchema     = "es"
+	channelSuffix     = ".transaction-handled"
+	publicationSuffix = "_feed"
+)
+
+func defaultTables() TableNames {
+	return TableNames{
+		Aggregates:    "aggregates",
+		Events:        "events",
+		Transactions:  "transactions",
+		Snapshots:     "snapshots",
+		Subscriptions: "subscriptions",
+		Outbox:        "outbox",
+		Projections:   "projections",
+	}
+}
+
+type ReplicationConfig struct {
+	ConnString     string
+	SlotName       string
+	Publication    string
+	StandbyTimeout time.Duration
+	Options        Options
+}
+
+type TableNames struct {
+	Aggregates    string
+	Events        string
+	Transactions  string
+	Snapshots     string
+	Subscriptions string
+	Outbox        string
+	Projections   string
+}
+
+type Options struct {
+	Schema      string
+	Tables      TableNames
+	Channel     string
+	Publication string
+}
+
+type PostgresDBOption func(*Options)
+
+func WithPostgresOptions(opts Options) PostgresDBOption {
+	return func(o *Options) {
+		*o = opts
+	}
+}
+
+func (o Options) withDefaults() Options {
+	if o.Schema == "" {
+		o.Schema = DefaultSchema
+	}
+	defaultTables := defaultTables()
+	defaults := []struct {
+		name  *string
+		value string
+	}{
+		{&o.Tables.Aggregates, defaultTables.Aggregates},
+		{&o.Tables.Events, defaultTables.Events},
+		{&o.Tables.Transactions, defaultTables.Transactions},
+		{&o.Tables.Snapshots, defaultTables.Snapshots},
+		{&o.Tables.Subscriptions, defaultTables.Subscriptions},
+		{&o.Tables.Outbox, defaultTables.Outbox},
+		{&o.Tables.Projections, defaultTables.Projections},
+	}
+	for _, d := range defaults {
+		if *d.name == "" {
+			*d.name = d.value
+		}
+	}
+	if o.Channel == "" {
+		o.Channel = o.Schema + channelSuffix
+	}
+	if o.Publication == "" {
+		o.Publication = o.Schema + publicationSuffix
+	}
+	return o
+}
+
+func (o Options) ChannelName() string {
+	return o.withDefaults().Channel
+}
+
+func (o Options) PublicationName() string {
+	return o.withDefaults().Publication
+}
+
+type tables struct {
+	opts     Options
+	replacer *strings.Replacer
+}
+
+func newTables(opts Options) *tables {
+	opts = opts.withDefaults()
+	t := &tables{opts: opts}
+	t.replacer = strings.NewReplacer(
+		"{aggregates}", t.qualified(opts.Tables.Aggregates),
+		"{events}", t.qualified(opts.Tables.Events),
+		"{transactions}", t.qualified(opts.Tables.Transactions),
+		"{snapshots}", t.qualified(opts.Tables.Snapshots),
+		"{subscriptions}", t.qualified(opts.Tables.Subscriptions),
+		"{outbox}", t.qualified(opts.Tables.Outbox),
+		"{projections}", t.qualified(opts.Tables.Projections),
+		"{aggregate_id_version_idx}", pgx.Identifier{t.eventsVersionIndex()}.Sanitize(),
+		"{transactions_command_id_idx}", pgx.Identifier{t.commandIDIndex()}.Sanitize(),
+		"{transactions_sequence_id_idx}", pgx.Identifier{opts.Tables.Transactions + "_sequence_id_idx"}.Sanitize(),
+		"{outbox_pending_idx}", pgx.Identifier{opts.Tables.Outbox + "_pending_idx"}.Sanitize(),
+		"{outbox_parked_idx}", pgx.Identifier{opts.Tables.Outbox + "_parked_idx"}.Sanitize(),
+		"{events_aggregates_id_fk}", pgx.Identifier{opts.Tables.Events + "_aggregates_id_fk"}.Sanitize(),
+		"{events_transaction_id_fk}", pgx.Identifier{opts.Tables.Events + "_transaction_id_fk"}.Sanitize(),
+		"{notify_transactions}", pgx.Identifier{"notify_" + opts.Tables.Transactions}.Sanitize(),
+		"{notify_transactions_trigger}", pgx.Identifier{"notify_" + opts.Tables.Transactions + "_trigger"}.Sanitize(),
+	)
+	return t
+}
+
+func (t *tables) eventsVersionIndex() string {
+	if t.opts.Tables.Events == defaultTables().Events {
+		return "aggregate_id_version_idx"
+	}
+	return t.opts.Tables.Events + "_aggregate_id_version_idx"
+}
+
+func (t *tables) commandIDIndex() string {
+	return t.opts.Tables.Transactions + "_command_id_idx"
+}
+
+func (t *tables) migrationsTable() string {
+	if t.opts.Tables == defaultTables() {
+		return migrationsTable
+	}
+	hash := fnv.New32a()
+	for _, name := range []string{
+		t.opts.Tables.Aggregates,
+		t.opts.Tables.Events,
+		t.opts.Tables.Transactions,
+		t.opts.Tables.Snapshots,
+		t.opts.Tables.Subscriptions,
+		t.opts.Tables.Outbox,
+		t.opts.Tables.Projections,
+	} {
+		_, _ = hash.Write([]byte(name + "\x00"))
+	}
+	return fmt.Sprintf("%s_%08x", migrationsTable, hash.Sum32())
+}
+
+func (t *tables) qualified(table string) string {
+	return pgx.Identifier{t.opts.Schema, table}.Sanitize()
+}
+
+func (t *tables) sql(query string) string {
+	return t.replacer.Replace(query)
+}
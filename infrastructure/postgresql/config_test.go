@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TablesTestCase struct {
+	description        string
+	opts               Options
+	query              string
+	expectedQuery      string
+	expectedMigrations string
+	expectedCommandID  string
+}
+
+func TestTables(t *testing.T) {
+	testCases := []TablesTestCase{
+		{
+			description:        "С таблицами по умолчанию должны сохраняться прежние имена индексов, функций и таблицы версий",
+			opts:               Options{},
+			query:              `{aggregate_id_version_idx} {transactions_command_id_idx} {outbox_pending_idx} {notify_transactions}`, //nolint:lll
+			expectedQuery:      `"aggregate_id_version_idx" "transactions_command_id_idx" "outbox_pending_idx" "notify_transactions"`, //nolint:lll
+			expectedMigrations: "schema_migrations",
+			expectedCommandID:  "transactions_command_id_idx",
+		},
+		{
+			description: "С собственными именами таблиц имена индексов, функций и таблицы версий должны выводиться из них",
+			opts: Options{
+				Schema: "billing",
+				Tables: TableNames{Events: "billing_events", Transactions: "billing_transactions", Outbox: "billing_outbox"},
+			},
+			query:              `{aggregate_id_version_idx} {transactions_command_id_idx} {outbox_pending_idx} {notify_transactions}`,                                        //nolint:lll
+			expectedQuery:      `"billing_events_aggregate_id_version_idx" "billing_transactions_command_id_idx" "billing_outbox_pending_idx" "notify_billing_transactions"`, //nolint:lll
+			expectedMigrations: "schema_migrations_8991032d",
+			expectedCommandID:  "billing_transactions_command_id_idx",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				names := newTables(tc.opts)
+
+				assert.Equal(t, tc.expectedQuery, names.sql(tc.query))
+				assert.Equal(t, tc.expectedMigrations, names.migrationsTable())
+				assert.Equal(t, tc.expectedCommandID, names.commandIDIndex())
+			},
+		)
+	}
+	t.Run("Разные наборы таблиц в одной схеме должны вести версии миграций в разных таблицах", func(t *testing.T) {
+		first := newTables(Options{Tables: TableNames{Transactions: "orders_transactions"}})
+		second := newTables(Options{Tables: TableNames{Transactions: "payments_transactions"}})
+
+		assert.NotEqual(t, first.migrationsTable(), second.migrationsTable())
+		assert.NotEqual(t, migrationsTable, first.migrationsTable())
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"math/rand/v2"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -14,77 +15,242 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-const shutdownPollIntervalMax = 500 * time.Millisecond
+const (
+	reconnectIntervalBase = 100 * time.Millisecond
+	reconnectIntervalMax  = 30 * time.Second
+)
+
+type ListenerStatus int
+
+const (
+	ListenerDisconnected ListenerStatus = iota
+	ListenerConnected
+	ListenerReconnecting
+)
+
+func (s ListenerStatus) String() string {
+	switch s {
+	case ListenerDisconnected:
+		return "disconnected"
+	case ListenerConnected:
+		return "connected"
+	case ListenerReconnecting:
+		return "reconnecting"
+	default:
+		return fmt.Sprintf("ListenerStatus(%d)", int(s))
+	}
+}
+
+type ListenerState struct {
+	Status           ListenerStatus
+	LastNotification time.Time
+	LastError        error
+	Reconnects       int
+}
+
+type listenerConn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+	Release()
+}
+
+type poolConn struct {
+	*pgxpool.Conn
+}
+
+func (c poolConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	return c.Conn.Conn().WaitForNotification(ctx)
+}
+
+func (c poolConn) Close(ctx context.Context) error {
+	return c.Hijack().Close(ctx)
+}
 
 type Listener struct {
 	channel     string
-	conn        *pgxpool.Conn
+	acquire     func(context.Context) (listenerConn, error)
+	conn        listenerConn
 	handle      func(context.Context, *pgconn.Notification)
+	onReconnect func(context.Context)
 	baseContext func() context.Context
 	inShutdown  atomic.Bool
+	mu          sync.RWMutex
+	state       ListenerState
 	log         *slog.Logger
 }
 
 func NewListener(
 	ch string,
-	conn *pgxpool.Conn,
+	acquire func(context.Context) (*pgxpool.Conn, error),
 	handle func(context.Context, *pgconn.Notification),
+	onReconnect func(context.Context),
 	baseContext func() context.Context,
 	log *slog.Logger,
 ) *Listener {
-	return &Listener{channel: ch, conn: conn, handle: handle, baseContext: baseContext, log: log}
+	return newListener(
+		ch,
+		func(ctx context.Context) (listenerConn, error) {
+			conn, err := acquire(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return poolConn{Conn: conn}, nil
+		},
+		handle,
+		onReconnect,
+		baseContext,
+		log,
+	)
+}
+
+func newListener(
+	ch string,
+	acquire func(context.Context) (listenerConn, error),
+	handle func(context.Context, *pgconn.Notification),
+	onReconnect func(context.Context),
+	baseContext func() context.Context,
+	log *slog.Logger,
+) *Listener {
+	return &Listener{
+		channel:     ch,
+		acquire:     acquire,
+		handle:      handle,
+		onReconnect: onReconnect,
+		baseContext: baseContext,
+		log:         log,
+	}
+}
+
+func (l *Listener) State() ListenerState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.state
 }
 
 func (l *Listener) StartListen() error {
-	defer l.conn.Release()
 	ctx := l.baseContext()
-	_, err := l.conn.Exec(ctx, fmt.Sprintf(`LISTEN "%s";`, l.channel))
+	defer l.release()
+	attempt := 0
+	for {
+		if attempt > 0 {
+			l.setStatus(ListenerReconnecting)
+			timer := time.NewTimer(reconnectInterval(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return l.shutdown()
+			case <-timer.C:
+			}
+		}
+		err := l.connect(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return l.shutdown()
+			}
+			l.fail(ctx, err)
+			attempt++
+			continue
+		}
+		if attempt > 0 {
+			l.mu.Lock()
+			l.state.Reconnects++
+			l.mu.Unlock()
+			if l.onReconnect != nil {
+				l.onReconnect(ctx)
+			}
+		}
+		err = l.listen(ctx)
+		if ctx.Err() != nil {
+			return l.shutdown()
+		}
+		l.fail(ctx, err)
+		l.drop(ctx)
+		attempt = 1
+	}
+}
+
+func (l *Listener) connect(ctx context.Context) error {
+	conn, err := l.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(ctx, fmt.Sprintf(`LISTEN "%s";`, l.channel))
 	if err != nil {
+		conn.Release()
 		return err
 	}
+	l.conn = conn
+	l.setStatus(ListenerConnected)
+	return nil
+}
+
+func (l *Listener) listen(ctx context.Context) error {
 	for {
-		select {
-		case <-ctx.Done():
-			l.inShutdown.Store(true)
-			return http.ErrServerClosed
-		case <-time.After(1 * time.Millisecond):
-			notification, errWait := l.conn.Conn().WaitForNotification(ctx)
-			if errWait != nil {
-				if !errors.Is(errWait, context.Canceled) {
-					l.log.ErrorContext(ctx, err.Error())
-				}
-			} else {
-				l.handle(ctx, notification)
-			}
+		notification, err := l.conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
 		}
+		l.mu.Lock()
+		l.state.LastNotification = time.Now()
+		l.mu.Unlock()
+		l.handle(ctx, notification)
+	}
+}
+
+func (l *Listener) fail(ctx context.Context, err error) {
+	if !errors.Is(err, context.Canceled) {
+		l.log.ErrorContext(ctx, err.Error())
+	}
+	l.mu.Lock()
+	l.state.Status = ListenerReconnecting
+	l.state.LastError = err
+	l.mu.Unlock()
+}
+
+func (l *Listener) drop(ctx context.Context) {
+	if l.conn == nil {
+		return
+	}
+	conn := l.conn
+	l.conn = nil
+	if err := conn.Close(ctx); err != nil {
+		l.log.ErrorContext(ctx, err.Error())
+	}
+}
+
+func (l *Listener) release() {
+	if l.conn != nil {
+		l.conn.Release()
+		l.conn = nil
 	}
+	l.setStatus(ListenerDisconnected)
+}
+
+func (l *Listener) shutdown() error {
+	l.inShutdown.Store(true)
+	return http.ErrServerClosed
+}
+
+func (l *Listener) setStatus(status ListenerStatus) {
+	l.mu.Lock()
+	l.state.Status = status
+	l.mu.Unlock()
 }
 
 func (l *Listener) Shutdown(ctx context.Context) error {
-	pollIntervalBase := time.Millisecond
-	nextPollInterval := func() time.Duration {
-		interval := pollIntervalBase + time.Duration(
-			rand.IntN(int(pollIntervalBase)), //nolint:gosec //is correct
-		)
-		pollIntervalBase *= 2
-		if pollIntervalBase > shutdownPollIntervalMax {
-			pollIntervalBase = shutdownPollIntervalMax
-		}
-		return interval
+	return waitShutdown(ctx, &l.inShutdown, l.log, "listener shutting down successfully")
+}
+
+func reconnectInterval(attempt int) time.Duration {
+	interval := reconnectIntervalBase
+	for i := 1; i < attempt && interval < reconnectIntervalMax; i++ {
+		interval *= 2
 	}
-	timer := time.NewTimer(nextPollInterval())
-	defer timer.Stop()
-	for {
-		if l.inShutdown.Load() {
-			l.log.InfoContext(ctx, "listener shutting down successfully")
-			return nil
-		}
-		select {
-		case <-ctx.Done():
-			l.log.ErrorContext(ctx, ctx.Err().Error())
-			return ctx.Err()
-		case <-timer.C:
-			timer.Reset(nextPollInterval())
-		}
+	if interval > reconnectIntervalMax {
+		interval = reconnectIntervalMax
 	}
+	return interval + time.Duration(
+		rand.Int64N(int64(interval)), //nolint:gosec //is correct
+	)
 }
@@ -0,0 +1,259 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listenerWaitTimeout = 5 * time.Second
+
+type fakeConn struct {
+	notifications chan *pgconn.Notification
+	broken        chan error
+	mu            sync.Mutex
+	statements    []string
+	closed        bool
+	released      bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		notifications: make(chan *pgconn.Notification),
+		broken:        make(chan error, 1),
+	}
+}
+
+func (c *fakeConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements = append(c.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-c.broken:
+		return nil, err
+	case notification := <-c.notifications:
+		return notification, nil
+	}
+}
+
+func (c *fakeConn) Close(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.released = true
+}
+
+func (c *fakeConn) state() ([]string, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.statements, c.closed, c.released
+}
+
+type ReconnectIntervalTestCase struct {
+	description string
+	attempt     int
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+func TestReconnectInterval(t *testing.T) {
+	testCases := []ReconnectIntervalTestCase{
+		{
+			description: "Первая попытка переподключения должна выполняться через базовый интервал с разбросом",
+			attempt:     1,
+			minInterval: reconnectIntervalBase,
+			maxInterval: 2 * reconnectIntervalBase,
+		},
+		{
+			description: "Каждая следующая попытка переподключения должна удваивать интервал",
+			attempt:     3,
+			minInterval: 4 * reconnectIntervalBase,
+			maxInterval: 8 * reconnectIntervalBase,
+		},
+		{
+			description: "Интервал переподключения не должен превышать максимальный интервал с разбросом",
+			attempt:     100,
+			minInterval: reconnectIntervalMax,
+			maxInterval: 2 * reconnectIntervalMax,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				for range 100 {
+					interval := reconnectInterval(tc.attempt)
+					assert.GreaterOrEqual(t, interval, tc.minInterval)
+					assert.Less(t, interval, tc.maxInterval)
+				}
+			},
+		)
+	}
+}
+
+func TestListener_StartListenMethod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		errAcquire = errors.New("acquire error")
+		errBroken  = errors.New("connection lost")
+		first      = newFakeConn()
+		second     = newFakeConn()
+		acquired   = make(chan *fakeConn, 2)
+		attempts   = []func() (listenerConn, error){
+			func() (listenerConn, error) { return nil, errAcquire },
+			func() (listenerConn, error) { acquired <- first; return first, nil },
+			func() (listenerConn, error) { acquired <- second; return second, nil },
+		}
+		handled     = make(chan *pgconn.Notification, 1)
+		reconnected = make(chan struct{}, 2)
+		mu          sync.Mutex
+	)
+	listener := newListener(
+		"transactions",
+		func(context.Context) (listenerConn, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			attempt := attempts[0]
+			if len(attempts) > 1 {
+				attempts = attempts[1:]
+			}
+			return attempt()
+		},
+		func(_ context.Context, notification *pgconn.Notification) {
+			handled <- notification
+		},
+		func(context.Context) {
+			reconnected <- struct{}{}
+		},
+		func() context.Context {
+			return ctx
+		},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	assert.Equal(t, ListenerDisconnected, listener.State().Status)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- listener.StartListen()
+	}()
+
+	waitConn(t, acquired, first)
+	waitSignal(t, reconnected)
+	state := listener.State()
+	assert.Equal(t, ListenerConnected, state.Status)
+	assert.Equal(t, errAcquire, state.LastError)
+	assert.Equal(t, 1, state.Reconnects)
+	statements, _, _ := first.state()
+	assert.Equal(t, []string{`LISTEN "transactions";`}, statements)
+
+	expected := &pgconn.Notification{Channel: "transactions", Payload: "{}"}
+	first.notifications <- expected
+	select {
+	case actual := <-handled:
+		assert.Same(t, expected, actual)
+	case <-time.After(listenerWaitTimeout):
+		require.FailNow(t, "notification was not handled")
+	}
+	assert.False(t, listener.State().LastNotification.IsZero())
+
+	first.broken <- errBroken
+	waitConn(t, acquired, second)
+	waitSignal(t, reconnected)
+	state = listener.State()
+	assert.Equal(t, ListenerConnected, state.Status)
+	assert.Equal(t, errBroken, state.LastError)
+	assert.Equal(t, 2, state.Reconnects)
+	_, closed, released := first.state()
+	assert.True(t, closed)
+	assert.False(t, released)
+	statements, _, _ = second.state()
+	assert.Equal(t, []string{`LISTEN "transactions";`}, statements)
+
+	cancel()
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+	case <-time.After(listenerWaitTimeout):
+		require.FailNow(t, "listener did not stop")
+	}
+	assert.NoError(t, listener.Shutdown(context.Background()))
+	assert.Equal(t, ListenerDisconnected, listener.State().Status)
+	_, closed, released = second.state()
+	assert.False(t, closed)
+	assert.True(t, released)
+}
+
+func waitConn(t *testing.T, acquired <-chan *fakeConn, expected *fakeConn) {
+	t.Helper()
+	select {
+	case actual := <-acquired:
+		require.Same(t, expected, actual)
+	case <-time.After(listenerWaitTimeout):
+		require.FailNow(t, "connection was not acquired")
+	}
+}
+
+func waitSignal(t *testing.T, signal <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-signal:
+	case <-time.After(listenerWaitTimeout):
+		require.FailNow(t, "listener did not reconnect")
+	}
+}
+
+type ListenerStatusTestCase struct {
+	description string
+	status      ListenerStatus
+	expected    string
+}
+
+func TestListenerStatus_StringMethod(t *testing.T) {
+	testCases := []ListenerStatusTestCase{
+		{
+			description: "Известный статус должен выводиться своим названием",
+			status:      ListenerReconnecting,
+			expected:    "reconnecting",
+		},
+		{
+			description: "Неизвестный статус должен выводиться числом без паники",
+			status:      ListenerStatus(7),
+			expected:    "ListenerStatus(7)",
+		},
+		{
+			description: "Отрицательный статус должен выводиться числом без паники",
+			status:      ListenerStatus(-1),
+			expected:    "ListenerStatus(-1)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				assert.Equal(t, tc.expected, tc.status.String())
+			},
+		)
+	}
+}
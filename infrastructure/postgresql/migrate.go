@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	migrationsTable         = "schema_migrations"
+	legacySubscriptionTable = "subscription"
+	publicationMigration    = "migrations/publication.tmpl"
+)
+
+//go:embed migrations/*.sql migrations/*.tmpl
+var migrations embed.FS
+
+type migrateConfig struct {
+	publication   bool
+	aggregateType string
+}
+
+type MigrateOption func(*migrateConfig)
+
+func WithPublication() MigrateOption {
+	return func(c *migrateConfig) {
+		c.publication = true
+	}
+}
+
+func WithAggregateTypeBackfill(aggregateType string) MigrateOption {
+	return func(c *migrateConfig) {
+		c.aggregateType = aggregateType
+	}
+}
+
+type migrationData struct {
+	Schema             string
+	Channel            string
+	Publication        string
+	PublicationName    string
+	LegacySubscription string
+}
+
+func Migrate(ctx context.Context, pool *pgxpool.Pool, opts Options, migrateOpts ...MigrateOption) (err error) {
+	cfg := &migrateConfig{}
+	for _, opt := range migrateOpts {
+		opt(cfg)
+	}
+	opts = opts.withDefaults()
+	names := newTables(opts)
+	schema := pgx.Identifier{opts.Schema}.Sanitize()
+	data := migrationData{
+		Schema:             schema,
+		Channel:            quoteLiteral(opts.Channel),
+		Publication:        pgx.Identifier{opts.Publication}.Sanitize(),
+		PublicationName:    quoteLiteral(opts.Publication),
+		LegacySubscription: quoteLiteral(names.qualified(legacySubscriptionTable)),
+	}
+	versionTable := names.qualified(names.migrationsTable())
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback(ctx))
+			return
+		}
+		err = tx.Commit(ctx)
+	}()
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, versionTable); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, `CREATE SCHEMA IF NOT EXISTS `+schema); err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		ctx,
+		`CREATE TABLE IF NOT EXISTS `+versionTable+` (version INTEGER PRIMARY KEY, applied_at TIMESTAMPTZ DEFAULT now() NOT NULL)`, //nolint:lll
+	)
+	if err != nil {
+		return err
+	}
+	var current int
+	err = tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM `+versionTable).Scan(&current)
+	if err != nil {
+		return err
+	}
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(files)
+	for _, name := range files {
+		version, parseErr := migrationVersion(name)
+		if parseErr != nil {
+			return parseErr
+		}
+		if version <= current {
+			continue
+		}
+		script, renderErr := renderMigration(name, data)
+		if renderErr != nil {
+			return renderErr
+		}
+		if _, err = tx.Exec(ctx, names.sql(script)); err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `INSERT INTO `+versionTable+` (version) VALUES ($1)`, version); err != nil {
+			return err
+		}
+	}
+	return postMigrate(ctx, tx, cfg, names, data)
+}
+
+func postMigrate(ctx context.Context, tx pgx.Tx, cfg *migrateConfig, names *tables, data migrationData) error {
+	if cfg.aggregateType != "" {
+		_, err := tx.Exec(ctx, names.sql(`UPDATE {aggregates} SET type = $1 WHERE type = ''`), cfg.aggregateType)
+		if err != nil {
+			return err
+		}
+	}
+	if !cfg.publication {
+		return nil
+	}
+	script, err := renderMigration(publicationMigration, data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, names.sql(script))
+	return err
+}
+
+func renderMigration(name string, data migrationData) (string, error) {
+	tmpl, err := template.ParseFS(migrations, name)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func migrationVersion(name string) (int, error) {
+	base := strings.TrimPrefix(name, "migrations/")
+	prefix, _, _ := strings.Cut(base, "_")
+	return strconv.Atoi(prefix)
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	require.NoError(t, err)
+	slices.Sort(files)
+	require.NotEmpty(t, files)
+	names := newTables(Options{})
+	data := migrationData{
+		Schema:             `"es"`,
+		Channel:            quoteLiteral("es.transaction-handled"),
+		Publication:        `"es_feed"`,
+		PublicationName:    quoteLiteral("es_feed"),
+		LegacySubscription: quoteLiteral(names.qualified(legacySubscriptionTable)),
+	}
+
+	t.Run("Версии миграций должны идти подряд, начиная с первой", func(t *testing.T) {
+		for i, name := range files {
+			version, versionErr := migrationVersion(name)
+			require.NoError(t, versionErr)
+			assert.Equal(t, i+1, version, name)
+		}
+	})
+
+	t.Run("Все миграции должны собираться без незаполненных подстановок и расширений", func(t *testing.T) {
+		for _, name := range append(files, publicationMigration) {
+			script, renderErr := renderMigration(name, data)
+			require.NoError(t, renderErr, name)
+			script = names.sql(script)
+			assert.NotContains(t, script, "{{", name)
+			assert.NotRegexp(t, `\{[a-z_]+\}`, script, name)
+			assert.NotContains(t, script, "EXTENSION", name)
+		}
+	})
+}
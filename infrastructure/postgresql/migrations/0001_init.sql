@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS {aggregates} (
+    id UUID PRIMARY KEY,
+    version INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS {transactions} (
+    id UUID PRIMARY KEY,
+    aggregate_id UUID NOT NULL,
+    sequence_id XID8 DEFAULT pg_current_xact_id() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS {events} (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_id UUID NOT NULL,
+    transaction_id UUID NOT NULL,
+    command_type INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    event_type INTEGER NOT NULL,
+    payload JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    CONSTRAINT {events_aggregates_id_fk} FOREIGN KEY (aggregate_id) REFERENCES {aggregates} (id) DEFERRABLE INITIALLY DEFERRED,
+    CONSTRAINT {events_transaction_id_fk} FOREIGN KEY (transaction_id) REFERENCES {transactions} (id) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS {aggregate_id_version_idx} ON {events} (aggregate_id, version);
+
+CREATE OR REPLACE FUNCTION {{.Schema}}.{notify_transactions}() RETURNS TRIGGER AS
+    $$
+    BEGIN
+        PERFORM pg_notify({{.Channel}}, row_to_json(NEW)::text);
+        RETURN NEW;
+    END;
+    $$
+    LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER {notify_transactions_trigger}
+    AFTER INSERT ON {transactions}
+    FOR EACH ROW
+    EXECUTE PROCEDURE {{.Schema}}.{notify_transactions}();
@@ -0,0 +1,4 @@
+ALTER TABLE {transactions} ADD COLUMN IF NOT EXISTS command_id TEXT;
+ALTER TABLE {transactions} ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ DEFAULT now() NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS {transactions_command_id_idx} ON {transactions} (command_id) WHERE command_id IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE {events} ADD COLUMN IF NOT EXISTS metadata JSONB DEFAULT '{}'::jsonb NOT NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS {subscriptions} (
+    name TEXT PRIMARY KEY,
+    last_sequence_id XID8 NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+DO $$
+BEGIN
+    IF to_regclass({{.LegacySubscription}}) IS NOT NULL THEN
+        INSERT INTO {subscriptions} (name, last_sequence_id)
+        SELECT 'default', last_sequence_id FROM {{.Schema}}.subscription WHERE id = 1
+        ON CONFLICT DO NOTHING;
+        DROP TABLE {{.Schema}}.subscription;
+    END IF;
+END
+$$;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS {transactions_sequence_id_idx} ON {transactions} (sequence_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS {outbox} (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER DEFAULT 0 NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS {outbox_pending_idx} ON {outbox} (id) WHERE sent_at IS NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS {projections} (
+    name TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (name, aggregate_id)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {snapshots} (
+    aggregate_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    PRIMARY KEY (aggregate_id, version)
+);
@@ -0,0 +1 @@
+ALTER TABLE {aggregates} ADD COLUMN IF NOT EXISTS type TEXT DEFAULT '' NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE {outbox} ADD COLUMN IF NOT EXISTS parked_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS {{.Schema}}.{outbox_pending_idx};
+CREATE INDEX IF NOT EXISTS {outbox_pending_idx} ON {outbox} (id) WHERE sent_at IS NULL AND parked_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE {events} ALTER COLUMN created_at TYPE TIMESTAMPTZ;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS {outbox_parked_idx} ON {outbox} (aggregate_id) WHERE sent_at IS NULL AND parked_at IS NOT NULL;
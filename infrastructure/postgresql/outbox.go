@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/outbox"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Outbox[K any] struct {
+	tables *tables
+}
+
+func NewOutbox[K any](opts Options) *Outbox[K] {
+	return &Outbox[K]{tables: newTables(opts)}
+}
+
+func (o *Outbox[K]) Enqueue(ctx context.Context, messages []*outbox.Message[K], tx Transaction) (err error) {
+	query := o.tables.sql(`INSERT INTO {outbox} (aggregate_id, payload) VALUES (@aggregateId, @payload)`)
+
+	batch := &pgx.Batch{}
+	for _, message := range messages {
+		args := pgx.NamedArgs{
+			"aggregateId": message.AggregateID,
+			"payload":     message.Event,
+		}
+		batch.Queue(query, args)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer func() {
+		closeErr := results.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for range messages {
+		_, err = results.Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Outbox[K]) GetPending(ctx context.Context, limit int, tx Transaction) ([]*outbox.Message[K], error) {
+	lockQuery := `SELECT pg_try_advisory_xact_lock(hashtext(@table))`
+	query := o.tables.sql(`SELECT id, aggregate_id, payload, attempts FROM {outbox} AS o WHERE sent_at IS NULL AND parked_at IS NULL AND NOT EXISTS (SELECT 1 FROM {outbox} AS p WHERE p.aggregate_id = o.aggregate_id AND p.parked_at IS NOT NULL AND p.sent_at IS NULL) ORDER BY id LIMIT @limit`) //nolint:lll
+	var locked bool
+	err := tx.QueryRow(ctx, lockQuery, pgx.NamedArgs{"table": o.tables.sql("{outbox}")}).Scan(&locked)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+	rows, err := tx.Query(ctx, query, pgx.NamedArgs{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*outbox.Message[K], 0, limit)
+	for rows.Next() {
+		var id int64
+		var aggregateID uuid.UUID
+		var event events.IntegrationEvent[K]
+		var attempts int
+		err = rows.Scan(&id, &aggregateID, &event, &attempts)
+		if err != nil {
+			return nil, err
+		}
+		message := outbox.NewMessage(aggregateID, event)
+		message.ID = id
+		message.Attempts = attempts
+		result = append(result, message)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *Outbox[K]) MarkSent(ctx context.Context, ids []int64, tx Transaction) error {
+	query := o.tables.sql(`UPDATE {outbox} SET sent_at = now(), attempts = attempts + 1, last_error = NULL WHERE id = ANY(@ids)`) //nolint:lll
+	_, err := tx.Exec(ctx, query, pgx.NamedArgs{"ids": ids})
+	return err
+}
+
+func (o *Outbox[K]) MarkFailed(ctx context.Context, ids []int64, reason string, tx Transaction) error {
+	query := o.tables.sql(`UPDATE {outbox} SET attempts = attempts + 1, last_error = @reason WHERE id = ANY(@ids)`)
+	args := pgx.NamedArgs{
+		"ids":    ids,
+		"reason": reason,
+	}
+	_, err := tx.Exec(ctx, query, args)
+	return err
+}
+
+func (o *Outbox[K]) Park(ctx context.Context, ids []int64, reason string, tx Transaction) error {
+	query := o.tables.sql(`UPDATE {outbox} SET attempts = attempts + 1, last_error = @reason, parked_at = now() WHERE id = ANY(@ids)`) //nolint:lll
+	args := pgx.NamedArgs{
+		"ids":    ids,
+		"reason": reason,
+	}
+	_, err := tx.Exec(ctx, query, args)
+	return err
+}
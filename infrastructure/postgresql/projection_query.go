@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FilterOperator string
+
+const (
+	FilterEq       FilterOperator = "="
+	FilterNe       FilterOperator = "<>"
+	FilterGt       FilterOperator = ">"
+	FilterGte      FilterOperator = ">="
+	FilterLt       FilterOperator = "<"
+	FilterLte      FilterOperator = "<="
+	FilterContains FilterOperator = "@>"
+)
+
+var ErrInvalidFilterOperator = errors.New("invalid filter operator")
+
+type ProjectionFilter struct {
+	Path     string
+	Operator FilterOperator
+	Value    any
+}
+
+func NewProjectionFilter(path string, operator FilterOperator, value any) ProjectionFilter {
+	return ProjectionFilter{
+		Path:     path,
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+type ProjectionQuery struct {
+	Filters    []ProjectionFilter
+	OrderBy    string
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+func (q ProjectionQuery) build(table, name string) (string, pgx.NamedArgs, error) {
+	var sb strings.Builder
+	args := pgx.NamedArgs{"name": name}
+	sb.WriteString(`SELECT payload FROM ` + table + ` WHERE name = @name`)
+	for i, filter := range q.Filters {
+		switch filter.Operator {
+		case FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterContains:
+		default:
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidFilterOperator, filter.Operator)
+		}
+		value, err := json.Marshal(filter.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		arg := fmt.Sprintf("filter%d", i)
+		fmt.Fprintf(&sb, ` AND payload #> %s %s @%s::jsonb`, jsonPathLiteral(filter.Path), filter.Operator, arg)
+		args[arg] = string(value)
+	}
+	sb.WriteString(` ORDER BY `)
+	if q.OrderBy != "" {
+		sb.WriteString(`payload #> ` + jsonPathLiteral(q.OrderBy))
+		if q.Descending {
+			sb.WriteString(` DESC`)
+		}
+		sb.WriteString(`, `)
+	}
+	sb.WriteString(`aggregate_id`)
+	if q.Limit > 0 {
+		sb.WriteString(` LIMIT @limit`)
+		args["limit"] = q.Limit
+	}
+	if q.Offset > 0 {
+		sb.WriteString(` OFFSET @offset`)
+		args["offset"] = q.Offset
+	}
+	return sb.String(), args, nil
+}
+
+func jsonPathLiteral(path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segment = strings.ReplaceAll(segment, `\`, `\\`)
+		segment = strings.ReplaceAll(segment, `"`, `\"`)
+		segments[i] = `"` + segment + `"`
+	}
+	literal := "{" + strings.Join(segments, ",") + "}"
+	return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+}
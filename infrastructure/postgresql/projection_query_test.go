@@ -0,0 +1,126 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type JSONPathLiteralTestCase struct {
+	description string
+	path        string
+	expected    string
+}
+
+func TestJSONPathLiteral(t *testing.T) {
+	testCases := []JSONPathLiteralTestCase{
+		{
+			description: "Путь из одного сегмента должен преобразовываться в литерал массива",
+			path:        "name",
+			expected:    `'{"name"}'`,
+		},
+		{
+			description: "Сегменты пути, разделенные точкой, должны становиться элементами массива",
+			path:        "address.city",
+			expected:    `'{"address","city"}'`,
+		},
+		{
+			description: "Одинарные кавычки в пути должны экранироваться, не позволяя выйти из строкового литерала",
+			path:        "x'}' OR 1=1; DROP TABLE events; --",
+			expected:    `'{"x''}'' OR 1=1; DROP TABLE events; --"}'`,
+		},
+		{
+			description: "Двойные кавычки в пути должны экранироваться внутри элемента массива",
+			path:        `a"b`,
+			expected:    `'{"a\"b"}'`,
+		},
+		{
+			description: "Обратная косая черта в пути должна экранироваться внутри элемента массива",
+			path:        `a\b`,
+			expected:    `'{"a\\b"}'`,
+		},
+		{
+			description: "Фигурные скобки и запятые в пути не должны разбивать элемент массива",
+			path:        "a,b}{c",
+			expected:    `'{"a,b}{c"}'`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				assert.Equal(t, tc.expected, jsonPathLiteral(tc.path))
+			},
+		)
+	}
+}
+
+type ProjectionQueryBuildTestCase struct {
+	description   string
+	query         ProjectionQuery
+	expectedSQL   string
+	expectedArgs  pgx.NamedArgs
+	expectedError error
+}
+
+func TestProjectionQuery_BuildMethod(t *testing.T) {
+	testCases := []ProjectionQueryBuildTestCase{
+		{
+			description:  "Пустой запрос должен выбирать все проекции с сортировкой по агрегату",
+			query:        ProjectionQuery{},
+			expectedSQL:  `SELECT payload FROM es.projections WHERE name = @name ORDER BY aggregate_id`,
+			expectedArgs: pgx.NamedArgs{"name": "users"},
+		},
+		{
+			description: "Фильтры, сортировка и пагинация должны передавать значения только через параметры",
+			query: ProjectionQuery{
+				Filters: []ProjectionFilter{
+					NewProjectionFilter("address.city", FilterEq, "'; DROP TABLE es.events; --"),
+					NewProjectionFilter("tags", FilterContains, []string{"admin"}),
+				},
+				OrderBy:    "age",
+				Descending: true,
+				Limit:      10,
+				Offset:     20,
+			},
+			expectedSQL: `SELECT payload FROM es.projections WHERE name = @name` +
+				` AND payload #> '{"address","city"}' = @filter0::jsonb` +
+				` AND payload #> '{"tags"}' @> @filter1::jsonb` +
+				` ORDER BY payload #> '{"age"}' DESC, aggregate_id LIMIT @limit OFFSET @offset`,
+			expectedArgs: pgx.NamedArgs{
+				"name":    "users",
+				"filter0": `"'; DROP TABLE es.events; --"`,
+				"filter1": `["admin"]`,
+				"limit":   10,
+				"offset":  20,
+			},
+		},
+		{
+			description: "Если оператор фильтра не поддерживается, то должна вернуться ошибка",
+			query: ProjectionQuery{
+				Filters: []ProjectionFilter{
+					NewProjectionFilter("name", "= '' OR 1=1; --", "test"),
+				},
+			},
+			expectedError: ErrInvalidFilterOperator,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				sql, args, err := tc.query.build("es.projections", "users")
+				if tc.expectedError != nil {
+					assert.ErrorIs(t, err, tc.expectedError)
+					assert.Empty(t, sql)
+					assert.Nil(t, args)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSQL, sql)
+				assert.Equal(t, tc.expectedArgs, args)
+			},
+		)
+	}
+}
@@ -0,0 +1,214 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type queryExecutor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+type ProjectionStoreOption func(*projectionTable)
+
+func WithProjectionIndex(path string) ProjectionStoreOption {
+	return func(pt *projectionTable) {
+		pt.indexes = append(pt.indexes, path)
+	}
+}
+
+func WithProjectionGINIndex() ProjectionStoreOption {
+	return func(pt *projectionTable) {
+		pt.gin = true
+	}
+}
+
+func WithProjectionStoreOptions(opts Options) ProjectionStoreOption {
+	return func(pt *projectionTable) {
+		pt.tables = newTables(opts)
+	}
+}
+
+type projectionTable struct {
+	name    string
+	tables  *tables
+	indexes []string
+	gin     bool
+}
+
+func newProjectionTable(name string, opts ...ProjectionStoreOption) *projectionTable {
+	table := &projectionTable{name: name, tables: newTables(Options{})}
+	for _, opt := range opts {
+		opt(table)
+	}
+	return table
+}
+
+type ProjectionStore[P any] struct {
+	pool  *pgxpool.Pool
+	table *projectionTable
+	key   func(projection P) (uuid.UUID, int)
+}
+
+func NewProjectionStore[P any](
+	pool *pgxpool.Pool,
+	name string,
+	key func(projection P) (uuid.UUID, int),
+	opts ...ProjectionStoreOption,
+) *ProjectionStore[P] {
+	return &ProjectionStore[P]{
+		pool:  pool,
+		table: newProjectionTable(name, opts...),
+		key:   key,
+	}
+}
+
+func (ps *ProjectionStore[P]) Transactional() *TransactionalProjectionStore[P] {
+	return &TransactionalProjectionStore[P]{
+		table: ps.table,
+		key:   ps.key,
+	}
+}
+
+func (ps *ProjectionStore[P]) EnsureIndexes(ctx context.Context) error {
+	for _, path := range ps.table.indexes {
+		query := fmt.Sprintf(
+			ps.table.tables.sql(`CREATE INDEX IF NOT EXISTS %s ON {projections} (name, (payload #> %s))`),
+			ps.table.indexName(path),
+			jsonPathLiteral(path),
+		)
+		if _, err := ps.pool.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	if ps.table.gin {
+		query := fmt.Sprintf(
+			ps.table.tables.sql(`CREATE INDEX IF NOT EXISTS %s ON {projections} USING GIN (payload jsonb_path_ops)`),
+			ps.table.indexName(""),
+		)
+		if _, err := ps.pool.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *ProjectionStore[P]) Save(ctx context.Context, projection P) error {
+	id, version := ps.key(projection)
+	return ps.table.save(ctx, ps.pool, id, version, projection)
+}
+
+func (ps *ProjectionStore[P]) Get(ctx context.Context, id uuid.UUID) (P, error) {
+	query := ps.table.tables.sql(`SELECT payload FROM {projections} WHERE name = @name AND aggregate_id = @aggregateId`)
+	args := pgx.NamedArgs{
+		"name":        ps.table.name,
+		"aggregateId": id,
+	}
+	var projection P
+	err := ps.pool.QueryRow(ctx, query, args).Scan(&projection)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return projection, repositories.ErrProjectionNotFound
+	}
+	return projection, err
+}
+
+func (ps *ProjectionStore[P]) GetMany(ctx context.Context, ids []uuid.UUID) ([]P, error) {
+	query := ps.table.tables.sql(`SELECT payload FROM {projections} WHERE name = @name AND aggregate_id = ANY(@ids) ORDER BY array_position(@ids::uuid[], aggregate_id)`) //nolint:lll
+	args := pgx.NamedArgs{
+		"name": ps.table.name,
+		"ids":  ids,
+	}
+	return ps.query(ctx, query, args)
+}
+
+func (ps *ProjectionStore[P]) Delete(ctx context.Context, id uuid.UUID) error {
+	query := ps.table.tables.sql(`DELETE FROM {projections} WHERE name = @name AND aggregate_id = @aggregateId`)
+	args := pgx.NamedArgs{
+		"name":        ps.table.name,
+		"aggregateId": id,
+	}
+	_, err := ps.pool.Exec(ctx, query, args)
+	return err
+}
+
+func (ps *ProjectionStore[P]) Find(ctx context.Context, q ProjectionQuery) ([]P, error) {
+	query, args, err := q.build(ps.table.tables.sql("{projections}"), ps.table.name)
+	if err != nil {
+		return nil, err
+	}
+	return ps.query(ctx, query, args)
+}
+
+func (ps *ProjectionStore[P]) query(ctx context.Context, query string, args pgx.NamedArgs) ([]P, error) {
+	rows, err := ps.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]P, 0)
+	for rows.Next() {
+		var projection P
+		if err = rows.Scan(&projection); err != nil {
+			return nil, err
+		}
+		result = append(result, projection)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type TransactionalProjectionStore[P any] struct {
+	table *projectionTable
+	key   func(projection P) (uuid.UUID, int)
+}
+
+func NewTransactionalProjectionStore[P any](
+	name string,
+	key func(projection P) (uuid.UUID, int),
+	opts ...ProjectionStoreOption,
+) *TransactionalProjectionStore[P] {
+	return &TransactionalProjectionStore[P]{
+		table: newProjectionTable(name, opts...),
+		key:   key,
+	}
+}
+
+func (ps *TransactionalProjectionStore[P]) Save(ctx context.Context, projection P, tx Transaction) error {
+	id, version := ps.key(projection)
+	return ps.table.save(ctx, tx, id, version, projection)
+}
+
+func (pt *projectionTable) save(
+	ctx context.Context,
+	executor queryExecutor,
+	id uuid.UUID,
+	version int,
+	projection any,
+) error {
+	query := pt.tables.sql(`INSERT INTO {projections} (name, aggregate_id, version, payload) VALUES (@name, @aggregateId, @version, @payload) ON CONFLICT (name, aggregate_id) DO UPDATE SET version = EXCLUDED.version, payload = EXCLUDED.payload, updated_at = now() WHERE {projections}.version <= EXCLUDED.version`) //nolint:lll
+	args := pgx.NamedArgs{
+		"name":        pt.name,
+		"aggregateId": id,
+		"version":     version,
+		"payload":     projection,
+	}
+	_, err := executor.Exec(ctx, query, args)
+	return err
+}
+
+func (pt *projectionTable) indexName(path string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(pt.name + "\x00" + path))
+	return pgx.Identifier{fmt.Sprintf("%s_%x_idx", pt.tables.opts.Tables.Projections, h.Sum64())}.Sanitize()
+}
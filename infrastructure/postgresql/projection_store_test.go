@@ -0,0 +1,24 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectionTable_IndexNameMethod(t *testing.T) {
+	t.Run("Имя индекса по умолчанию должно сохранять прежний префикс", func(t *testing.T) {
+		table := newProjectionTable("roles")
+
+		assert.Regexp(t, `^"projections_[0-9a-f]+_idx"$`, table.indexName("name"))
+	})
+	t.Run("Имя индекса должно выводиться из настроенной таблицы проекций", func(t *testing.T) {
+		table := newProjectionTable(
+			"roles",
+			WithProjectionStoreOptions(Options{Tables: TableNames{Projections: "billing_projections"}}),
+		)
+
+		assert.Regexp(t, `^"billing_projections_[0-9a-f]+_idx"$`, table.indexName("name"))
+		assert.NotEqual(t, table.indexName("name"), table.indexName(""))
+	})
+}
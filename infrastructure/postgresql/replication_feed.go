@@ -0,0 +1,278 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/google/uuid"
+	"github.com/jackc/pglogrepl"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+const (
+	DefaultStandbyTimeout = 10 * time.Second
+	outputPlugin          = "pgoutput"
+	duplicateObjectCode   = "42710"
+)
+
+// ReplicationFeed uses the confirmed LSN of the slot as its checkpoint and confirms a commit only once handled.
+type ReplicationFeed struct {
+	cfg         *ReplicationConfig
+	connect     func(context.Context) (*pgconn.PgConn, error)
+	handle      func(context.Context, *transactions.Transaction) error
+	baseContext func() context.Context
+	inShutdown  atomic.Bool
+	log         *slog.Logger
+}
+
+type replicationState struct {
+	relations map[uint32]*pglogrepl.RelationMessage
+	pending   []*transactions.Transaction
+	ackLSN    pglogrepl.LSN
+}
+
+func NewReplicationFeed(
+	cfg *ReplicationConfig,
+	handle func(context.Context, *transactions.Transaction) error,
+	baseContext func() context.Context,
+	log *slog.Logger,
+) *ReplicationFeed {
+	resolved := *cfg
+	resolved.Options = cfg.Options.withDefaults()
+	if resolved.Publication == "" {
+		resolved.Publication = resolved.Options.Publication
+	}
+	return &ReplicationFeed{
+		cfg: &resolved,
+		connect: func(ctx context.Context) (*pgconn.PgConn, error) {
+			return pgconn.Connect(ctx, resolved.ConnString)
+		},
+		handle:      handle,
+		baseContext: baseContext,
+		log:         log,
+	}
+}
+
+func (f *ReplicationFeed) StartFeed() error {
+	ctx := f.baseContext()
+	attempt := 0
+	for {
+		if attempt > 0 {
+			timer := time.NewTimer(reconnectInterval(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return f.shutdown()
+			case <-timer.C:
+			}
+		}
+		started, err := f.run(ctx)
+		if ctx.Err() != nil {
+			return f.shutdown()
+		}
+		if !errors.Is(err, context.Canceled) {
+			f.log.ErrorContext(ctx, err.Error(), slog.Int("attempt", attempt+1))
+		}
+		if started {
+			attempt = 1
+			continue
+		}
+		attempt++
+	}
+}
+
+func (f *ReplicationFeed) run(ctx context.Context) (bool, error) {
+	conn, err := f.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if closeErr := conn.Close(context.Background()); closeErr != nil {
+			f.log.ErrorContext(ctx, closeErr.Error())
+		}
+	}()
+	if err = f.createSlot(ctx, conn); err != nil {
+		return false, err
+	}
+	err = pglogrepl.StartReplication(
+		ctx,
+		conn,
+		f.cfg.SlotName,
+		0,
+		pglogrepl.StartReplicationOptions{
+			PluginArgs: []string{
+				"proto_version '1'",
+				"publication_names " + quoteLiteral(f.cfg.Publication),
+			},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return true, f.consume(ctx, conn)
+}
+
+func (f *ReplicationFeed) shutdown() error {
+	f.inShutdown.Store(true)
+	return http.ErrServerClosed
+}
+
+func (f *ReplicationFeed) Shutdown(ctx context.Context) error {
+	return waitShutdown(ctx, &f.inShutdown, f.log, "replication feed shutting down successfully")
+}
+
+func (f *ReplicationFeed) createSlot(ctx context.Context, conn *pgconn.PgConn) error {
+	_, err := pglogrepl.CreateReplicationSlot(
+		ctx,
+		conn,
+		f.cfg.SlotName,
+		outputPlugin,
+		pglogrepl.CreateReplicationSlotOptions{Mode: pglogrepl.LogicalReplication},
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == duplicateObjectCode {
+		return nil
+	}
+	return err
+}
+
+func (f *ReplicationFeed) consume(ctx context.Context, conn *pgconn.PgConn) error {
+	standbyTimeout := f.cfg.StandbyTimeout
+	if standbyTimeout <= 0 {
+		standbyTimeout = DefaultStandbyTimeout
+	}
+	state := &replicationState{relations: make(map[uint32]*pglogrepl.RelationMessage)}
+	nextStandbyDeadline := time.Now().Add(standbyTimeout)
+	for {
+		if time.Now().After(nextStandbyDeadline) {
+			if err := f.sendStatus(ctx, conn, state.ackLSN); err != nil {
+				return err
+			}
+			nextStandbyDeadline = time.Now().Add(standbyTimeout)
+		}
+		receiveCtx, cancel := context.WithDeadline(ctx, nextStandbyDeadline)
+		rawMsg, err := conn.ReceiveMessage(receiveCtx)
+		cancel()
+		if err != nil {
+			if pgconn.Timeout(err) && ctx.Err() == nil {
+				continue
+			}
+			return err
+		}
+		if errMsg, ok := rawMsg.(*pgproto3.ErrorResponse); ok {
+			return pgconn.ErrorResponseToPgError(errMsg)
+		}
+		msg, ok := rawMsg.(*pgproto3.CopyData)
+		if !ok || len(msg.Data) == 0 {
+			continue
+		}
+		switch msg.Data[0] {
+		case pglogrepl.PrimaryKeepaliveMessageByteID:
+			pkm, errParse := pglogrepl.ParsePrimaryKeepaliveMessage(msg.Data[1:])
+			if errParse != nil {
+				return errParse
+			}
+			if pkm.ReplyRequested {
+				nextStandbyDeadline = time.Time{}
+			}
+		case pglogrepl.XLogDataByteID:
+			xld, errParse := pglogrepl.ParseXLogData(msg.Data[1:])
+			if errParse != nil {
+				return errParse
+			}
+			if err = f.process(ctx, state, xld.WALData); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *ReplicationFeed) process(
+	ctx context.Context,
+	state *replicationState,
+	walData []byte,
+) error {
+	logicalMsg, err := pglogrepl.Parse(walData)
+	if err != nil {
+		return err
+	}
+	switch msg := logicalMsg.(type) {
+	case *pglogrepl.RelationMessage:
+		state.relations[msg.RelationID] = msg
+	case *pglogrepl.BeginMessage:
+		state.pending = state.pending[:0]
+	case *pglogrepl.InsertMessage:
+		rel, ok := state.relations[msg.RelationID]
+		if !ok {
+			return fmt.Errorf("unknown relation %d", msg.RelationID)
+		}
+		if rel.Namespace != f.cfg.Options.Schema || rel.RelationName != f.cfg.Options.Tables.Transactions {
+			return nil
+		}
+		transaction, errDecode := decodeTransaction(rel, msg.Tuple)
+		if errDecode != nil {
+			return errDecode
+		}
+		state.pending = append(state.pending, transaction)
+	case *pglogrepl.CommitMessage:
+		pending := state.pending
+		state.pending = state.pending[:0]
+		for _, transaction := range pending {
+			if err = f.handle(ctx, transaction); err != nil {
+				return fmt.Errorf("handle transaction %s at lsn %s: %w", transaction.ID, msg.CommitLSN, err)
+			}
+		}
+		state.ackLSN = msg.TransactionEndLSN
+	}
+	return nil
+}
+
+func (f *ReplicationFeed) sendStatus(
+	ctx context.Context,
+	conn *pgconn.PgConn,
+	lsn pglogrepl.LSN,
+) error {
+	if lsn == 0 {
+		return nil
+	}
+	return pglogrepl.SendStandbyStatusUpdate(
+		ctx,
+		conn,
+		pglogrepl.StandbyStatusUpdate{WALWritePosition: lsn},
+	)
+}
+
+func decodeTransaction(
+	rel *pglogrepl.RelationMessage,
+	tuple *pglogrepl.TupleData,
+) (*transactions.Transaction, error) {
+	values := make(map[string]string, len(tuple.Columns))
+	for i, col := range tuple.Columns {
+		if i < len(rel.Columns) && col.DataType == pglogrepl.TupleDataTypeText {
+			values[rel.Columns[i].Name] = string(col.Data)
+		}
+	}
+	id, err := uuid.Parse(values["id"])
+	if err != nil {
+		return nil, err
+	}
+	aggregateID, err := uuid.Parse(values["aggregate_id"])
+	if err != nil {
+		return nil, err
+	}
+	sequenceID, err := strconv.ParseInt(values["sequence_id"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	transaction := transactions.NewTransaction(id, aggregateID, sequenceID)
+	transaction.CommandID = values["command_id"]
+	return transaction, nil
+}
@@ -0,0 +1,239 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/google/uuid"
+	"github.com/jackc/pglogrepl"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	transactionsRelationID = 1
+	eventsRelationID       = 2
+)
+
+type ReplicationFeedProcessTestCase struct {
+	description   string
+	messages      [][]byte
+	handleErr     error
+	dataAssertion func(handled []*transactions.Transaction, state *replicationState, actual error)
+}
+
+func TestReplicationFeed_ProcessMethod(t *testing.T) {
+	var (
+		errExpected     = errors.New("test error")
+		firstID         = uuid.New()
+		secondID        = uuid.New()
+		aggregateID     = uuid.New()
+		commitLSN       = pglogrepl.LSN(100)
+		endLSN          = pglogrepl.LSN(120)
+		previousLSN     = pglogrepl.LSN(50)
+		transactionsRel = relationMessage(
+			transactionsRelationID,
+			DefaultSchema,
+			"transactions",
+			"id", "aggregate_id", "sequence_id", "command_id",
+		)
+		eventsRel = relationMessage(eventsRelationID, DefaultSchema, "events", "id", "aggregate_id")
+	)
+	testCases := []ReplicationFeedProcessTestCase{
+		{
+			description: "При фиксации транзакции БД записи transactions должны передаваться обработчику, а LSN подтверждаться", //nolint:lll
+			messages: [][]byte{
+				transactionsRel,
+				beginMessage(),
+				insertMessage(transactionsRelationID, firstID.String(), aggregateID.String(), "1", "command"),
+				insertMessage(transactionsRelationID, secondID.String(), aggregateID.String(), "2", ""),
+				commitMessage(commitLSN, endLSN),
+			},
+			dataAssertion: func(handled []*transactions.Transaction, state *replicationState, actual error) {
+				assert.NoError(t, actual)
+				require.Len(t, handled, 2)
+				assert.Equal(t, firstID, handled[0].ID)
+				assert.Equal(t, aggregateID, handled[0].AggregateID)
+				assert.Equal(t, int64(1), handled[0].SequenceID)
+				assert.Equal(t, "command", handled[0].CommandID)
+				assert.Equal(t, secondID, handled[1].ID)
+				assert.Equal(t, int64(2), handled[1].SequenceID)
+				assert.Equal(t, endLSN, state.ackLSN)
+				assert.Empty(t, state.pending)
+			},
+		},
+		{
+			description: "Записи других таблиц не должны передаваться обработчику, а LSN должен подтверждаться",
+			messages: [][]byte{
+				eventsRel,
+				beginMessage(),
+				insertMessage(eventsRelationID, firstID.String(), aggregateID.String()),
+				commitMessage(commitLSN, endLSN),
+			},
+			dataAssertion: func(handled []*transactions.Transaction, state *replicationState, actual error) {
+				assert.NoError(t, actual)
+				assert.Empty(t, handled)
+				assert.Equal(t, endLSN, state.ackLSN)
+			},
+		},
+		{
+			description: "Если обработчик завершился ошибкой, то должна вернуться ошибка, а LSN не должен подтверждаться",
+			messages: [][]byte{
+				transactionsRel,
+				beginMessage(),
+				insertMessage(transactionsRelationID, firstID.String(), aggregateID.String(), "1", ""),
+				commitMessage(commitLSN, endLSN),
+			},
+			handleErr: errExpected,
+			dataAssertion: func(handled []*transactions.Transaction, state *replicationState, actual error) {
+				assert.ErrorIs(t, actual, errExpected)
+				assert.Len(t, handled, 1)
+				assert.Equal(t, previousLSN, state.ackLSN)
+				assert.Empty(t, state.pending)
+			},
+		},
+		{
+			description: "Если запись относится к неизвестному отношению, то должна вернуться ошибка",
+			messages: [][]byte{
+				beginMessage(),
+				insertMessage(transactionsRelationID, firstID.String(), aggregateID.String(), "1", ""),
+			},
+			dataAssertion: func(handled []*transactions.Transaction, state *replicationState, actual error) {
+				assert.EqualError(t, actual, "unknown relation 1")
+				assert.Empty(t, handled)
+				assert.Equal(t, previousLSN, state.ackLSN)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				var handled []*transactions.Transaction
+				feed := NewReplicationFeed(
+					&ReplicationConfig{SlotName: "test"},
+					func(_ context.Context, transaction *transactions.Transaction) error {
+						handled = append(handled, transaction)
+						return tc.handleErr
+					},
+					context.Background,
+					slog.New(slog.NewTextHandler(io.Discard, nil)),
+				)
+				state := &replicationState{
+					relations: make(map[uint32]*pglogrepl.RelationMessage),
+					ackLSN:    previousLSN,
+				}
+				var err error
+				for _, message := range tc.messages {
+					if err = feed.process(context.Background(), state, message); err != nil {
+						break
+					}
+				}
+				tc.dataAssertion(handled, state, err)
+			},
+		)
+	}
+}
+
+func TestReplicationFeed_StartFeedMethod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		errConnect    = errors.New("connection refused")
+		attempts      atomic.Int32
+		attemptedAt   = make(chan time.Time, 3)
+		expectedCalls = 3
+	)
+	feed := NewReplicationFeed(
+		&ReplicationConfig{SlotName: "test"},
+		func(context.Context, *transactions.Transaction) error { return nil },
+		func() context.Context { return ctx },
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	feed.connect = func(context.Context) (*pgconn.PgConn, error) {
+		if int(attempts.Add(1)) <= expectedCalls {
+			attemptedAt <- time.Now()
+		}
+		return nil, errConnect
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- feed.StartFeed()
+	}()
+	var previous time.Time
+	for attempt := range expectedCalls {
+		select {
+		case current := <-attemptedAt:
+			if attempt > 0 {
+				assert.GreaterOrEqual(t, current.Sub(previous), reconnectIntervalBase<<(attempt-1))
+			}
+			previous = current
+		case <-time.After(listenerWaitTimeout):
+			require.FailNow(t, "replication feed did not reconnect")
+		}
+	}
+	cancel()
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+	case <-time.After(listenerWaitTimeout):
+		require.FailNow(t, "replication feed did not stop")
+	}
+	assert.NoError(t, feed.Shutdown(context.Background()))
+}
+
+func relationMessage(id uint32, namespace, name string, columns ...string) []byte {
+	msg := []byte{byte(pglogrepl.MessageTypeRelation)}
+	msg = binary.BigEndian.AppendUint32(msg, id)
+	msg = append(append(msg, namespace...), 0)
+	msg = append(append(msg, name...), 0)
+	msg = append(msg, 'd')
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(columns)))
+	for _, column := range columns {
+		msg = append(msg, 0)
+		msg = append(append(msg, column...), 0)
+		msg = binary.BigEndian.AppendUint32(msg, 0)
+		msg = binary.BigEndian.AppendUint32(msg, 0)
+	}
+	return msg
+}
+
+func beginMessage() []byte {
+	msg := []byte{byte(pglogrepl.MessageTypeBegin)}
+	msg = binary.BigEndian.AppendUint64(msg, 0)
+	msg = binary.BigEndian.AppendUint64(msg, 0)
+	return binary.BigEndian.AppendUint32(msg, 1)
+}
+
+func insertMessage(relationID uint32, values ...string) []byte {
+	msg := []byte{byte(pglogrepl.MessageTypeInsert)}
+	msg = binary.BigEndian.AppendUint32(msg, relationID)
+	msg = append(msg, 'N')
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(values)))
+	for _, value := range values {
+		if value == "" {
+			msg = append(msg, pglogrepl.TupleDataTypeNull)
+			continue
+		}
+		msg = append(msg, pglogrepl.TupleDataTypeText)
+		msg = binary.BigEndian.AppendUint32(msg, uint32(len(value)))
+		msg = append(msg, value...)
+	}
+	return msg
+}
+
+func commitMessage(commitLSN, endLSN pglogrepl.LSN) []byte {
+	msg := []byte{byte(pglogrepl.MessageTypeCommit), 0}
+	msg = binary.BigEndian.AppendUint64(msg, uint64(commitLSN))
+	msg = binary.BigEndian.AppendUint64(msg, uint64(endLSN))
+	return binary.BigEndian.AppendUint64(msg, 0)
+}
@@ -0,0 +1,45 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+const shutdownPollIntervalMax = 500 * time.Millisecond
+
+func waitShutdown(
+	ctx context.Context,
+	inShutdown *atomic.Bool,
+	log *slog.Logger,
+	msg string,
+) error {
+	pollIntervalBase := time.Millisecond
+	nextPollInterval := func() time.Duration {
+		interval := pollIntervalBase + time.Duration(
+			rand.IntN(int(pollIntervalBase)), //nolint:gosec //is correct
+		)
+		pollIntervalBase *= 2
+		if pollIntervalBase > shutdownPollIntervalMax {
+			pollIntervalBase = shutdownPollIntervalMax
+		}
+		return interval
+	}
+	timer := time.NewTimer(nextPollInterval())
+	defer timer.Stop()
+	for {
+		if inShutdown.Load() {
+			log.InfoContext(ctx, msg)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			log.ErrorContext(ctx, ctx.Err().Error())
+			return ctx.Err()
+		case <-timer.C:
+			timer.Reset(nextPollInterval())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package postgresql
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *PostgresDB[T, S]) ReadAll(
+	ctx context.Context,
+	fromPosition events.Position,
+	limit int,
+	filter events.Filter,
+	tx Transaction,
+) iter.Seq2[events.StreamEvent[T], error] {
+	return repositories.ReadPages(
+		fromPosition,
+		limit,
+		func(after events.Position, pageSize int) ([]events.StreamEvent[T], error) {
+			query, args := db.buildReadAllQuery(after, pageSize, filter)
+			rows, err := tx.Query(ctx, query, args)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			result := make([]events.StreamEvent[T], 0, pageSize)
+			for rows.Next() {
+				var streamEvent events.StreamEvent[T]
+				var sequenceID string
+				var createdAt time.Time
+				err = rows.Scan(
+					&sequenceID,
+					&streamEvent.AggregateType,
+					&streamEvent.AggregateID,
+					&streamEvent.TransactionID,
+					&streamEvent.Version,
+					&streamEvent.CommandType,
+					&streamEvent.Type,
+					&streamEvent.Payload,
+					&streamEvent.Metadata,
+					&createdAt,
+				)
+				if err != nil {
+					return nil, err
+				}
+				streamEvent.Position.SequenceID, err = strconv.ParseInt(sequenceID, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				streamEvent.Position.Version = streamEvent.Version
+				streamEvent.CreatedAt = &createdAt
+				result = append(result, streamEvent)
+			}
+			if err = rows.Err(); err != nil {
+				return nil, err
+			}
+			return result, nil
+		},
+	)
+}
+
+func (db *PostgresDB[T, S]) buildReadAllQuery(
+	after events.Position,
+	limit int,
+	filter events.Filter,
+) (string, pgx.NamedArgs) {
+	var sb strings.Builder
+	sb.WriteString(db.tables.sql(`SELECT t.sequence_id::text, COALESCE(a.type, ''), e.aggregate_id, e.transaction_id, e.version, e.command_type, e.event_type, e.payload, e.metadata, e.created_at FROM {transactions} AS t JOIN {events} AS e ON e.transaction_id = t.id LEFT JOIN {aggregates} AS a ON a.id = t.aggregate_id WHERE (t.sequence_id > @sequenceId::xid8 OR t.sequence_id = @sequenceId::xid8 AND e.version > @version) AND t.sequence_id < pg_snapshot_xmin(pg_current_snapshot())`)) //nolint:lll
+	args := pgx.NamedArgs{
+		"sequenceId": after.SequenceID,
+		"version":    after.Version,
+		"limit":      limit,
+	}
+	if len(filter.AggregateIDs) > 0 {
+		sb.WriteString(` AND e.aggregate_id = ANY(@aggregateIds)`)
+		args["aggregateIds"] = filter.AggregateIDs
+	}
+	if len(filter.EventTypes) > 0 {
+		sb.WriteString(` AND e.event_type = ANY(@eventTypes)`)
+		args["eventTypes"] = filter.EventTypes
+	}
+	if len(filter.CommandTypes) > 0 {
+		sb.WriteString(` AND e.command_type = ANY(@commandTypes)`)
+		args["commandTypes"] = filter.CommandTypes
+	}
+	if len(filter.AggregateTypes) > 0 {
+		sb.WriteString(` AND COALESCE(a.type, '') = ANY(@aggregateTypes)`)
+		args["aggregateTypes"] = filter.AggregateTypes
+	}
+	sb.WriteString(` ORDER BY t.sequence_id, e.version LIMIT @limit`)
+	return sb.String(), args
+}
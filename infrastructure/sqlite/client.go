@@ -0,0 +1,594 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	driverName          = "sqlite"
+	commandIDConstraint = "transactions.command_id"
+)
+
+type SQLiteDB[T, S any] struct {
+	db *sql.DB
+}
+
+func NewSQLiteDB[T, S any](ctx context.Context, dsn string) (*SQLiteDB[T, S], error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	for _, pragma := range []string{
+		`PRAGMA journal_mode = WAL`,
+		`PRAGMA busy_timeout = 5000`,
+		`PRAGMA synchronous = NORMAL`,
+	} {
+		if _, err = db.ExecContext(ctx, pragma); err != nil {
+			return nil, errors.Join(err, db.Close())
+		}
+	}
+	if err = Migrate(ctx, db); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return &SQLiteDB[T, S]{db: db}, nil
+}
+
+func (db *SQLiteDB[T, S]) DB() *sql.DB {
+	return db.db
+}
+
+func (db *SQLiteDB[T, S]) Close() error {
+	return db.db.Close()
+}
+
+func (db *SQLiteDB[T, S]) Begin(ctx context.Context) (*sql.Tx, error) {
+	return db.db.BeginTx(ctx, nil)
+}
+
+func (db *SQLiteDB[T, S]) Commit(_ context.Context, tx *sql.Tx) error {
+	return tx.Commit()
+}
+
+func (db *SQLiteDB[T, S]) Rollback(_ context.Context, tx *sql.Tx) error {
+	return tx.Rollback()
+}
+
+func (db *SQLiteDB[T, S]) GetSnapshot(
+	ctx context.Context,
+	id uuid.UUID,
+	versionAfter *int,
+	tx *sql.Tx,
+) (int, S, error) {
+	query := `SELECT version, payload FROM snapshots WHERE aggregate_id = ? ORDER BY version DESC LIMIT 1`
+	args := []any{id}
+	if versionAfter != nil {
+		query = `SELECT version, payload FROM snapshots WHERE aggregate_id = ? AND version < ? ORDER BY version DESC LIMIT 1` //nolint:lll
+		args = append(args, *versionAfter)
+	}
+	return querySnapshot[S](ctx, tx, query, args...)
+}
+
+func (db *SQLiteDB[T, S]) GetSnapshotAsOf(
+	ctx context.Context,
+	id uuid.UUID,
+	asOf time.Time,
+	tx *sql.Tx,
+) (int, S, error) {
+	query := `SELECT s.version, s.payload FROM snapshots AS s JOIN events AS e ON e.aggregate_id = s.aggregate_id AND e.version = s.version WHERE s.aggregate_id = ? AND e.created_at <= ? ORDER BY s.version DESC LIMIT 1` //nolint:lll
+	return querySnapshot[S](ctx, tx, query, id, asOf.UnixMicro())
+}
+
+func (db *SQLiteDB[T, S]) GetEvents(
+	ctx context.Context,
+	id uuid.UUID,
+	fromVersion int,
+	toVersion *int,
+	tx *sql.Tx,
+) ([]events.Event[T], error) {
+	query := `SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM events WHERE aggregate_id = ? AND version >= ? ORDER BY version` //nolint:lll
+	args := []any{id, fromVersion}
+	if toVersion != nil {
+		query = `SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM events WHERE aggregate_id = ? AND version >= ? AND version <= ? ORDER BY version` //nolint:lll
+		args = append(args, *toVersion)
+	}
+	return queryEvents[T](ctx, tx, query, args...)
+}
+
+func (db *SQLiteDB[T, S]) GetEventsUntil(
+	ctx context.Context,
+	id uuid.UUID,
+	fromVersion int,
+	until time.Time,
+	tx *sql.Tx,
+) ([]events.Event[T], error) {
+	query := `SELECT aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at FROM events WHERE aggregate_id = ? AND version >= ? AND created_at <= ? ORDER BY version` //nolint:lll
+	return queryEvents[T](ctx, tx, query, id, fromVersion, until.UnixMicro())
+}
+
+func (db *SQLiteDB[T, S]) GetUnhandledEvents(
+	ctx context.Context,
+	id uuid.UUID,
+	firstSequenceID, lastSequenceID int64,
+	tx *sql.Tx,
+) ([]events.Event[T], error) {
+	query := `SELECT e.aggregate_id, e.transaction_id, e.version, e.command_type, e.event_type, e.payload, e.metadata, e.created_at FROM transactions AS t JOIN events AS e ON e.transaction_id = t.id WHERE t.sequence_id > ? AND t.sequence_id <= ? AND t.aggregate_id = ? ORDER BY t.sequence_id, e.version` //nolint:lll
+	rows, err := tx.QueryContext(ctx, query, firstSequenceID, lastSequenceID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var newEvents []events.Event[T]
+	for rows.Next() {
+		event, errScan := scanEvent[T](rows)
+		if errScan != nil {
+			return []events.Event[T]{}, errScan
+		}
+		newEvents = append(newEvents, event)
+	}
+	if err = rows.Err(); err != nil {
+		return []events.Event[T]{}, err
+	}
+	return newEvents, nil
+}
+
+func (db *SQLiteDB[T, S]) UpdateOrCreateAggregate(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	reader entities.AggregateReader[T],
+	snapshot S,
+	tx *sql.Tx,
+) error {
+	currentVersion, nextVersion := reader.BaseVersion(), reader.Version()
+	var err error
+	if currentVersion == 0 {
+		err = db.createVersion(ctx, reader.ID(), reader.Type(), nextVersion, tx)
+	} else {
+		err = db.updateVersion(ctx, reader.ID(), reader.Type(), currentVersion, nextVersion, tx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if nextVersion/reader.Cap() > currentVersion/reader.Cap() {
+		err = db.insertSnapshot(ctx, reader.ID(), nextVersion, snapshot, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UnixMicro()
+	err = db.insertEvents(ctx, reader.Changes(), now, tx)
+	if err != nil {
+		return err
+	}
+	return db.insertTransaction(ctx, transaction, now, tx)
+}
+
+func (db *SQLiteDB[T, S]) GetTransactions(
+	ctx context.Context,
+	afterSequenceID int64,
+	limit int,
+	tx *sql.Tx,
+) ([]*transactions.Transaction, error) {
+	return queryTransactions(ctx, tx, afterSequenceID, limit)
+}
+
+func (db *SQLiteDB[T, S]) GetAggregateIDs(
+	ctx context.Context,
+	afterID uuid.UUID,
+	limit int,
+	aggregateType string,
+	tx *sql.Tx,
+) ([]uuid.UUID, error) {
+	query := `SELECT id FROM aggregates WHERE id > ? AND (? = '' OR type = ?) ORDER BY id LIMIT ?`
+	rows, err := tx.QueryContext(ctx, query, afterID, aggregateType, aggregateType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *SQLiteDB[T, S]) GetAggregateTypes(
+	ctx context.Context,
+	ids []uuid.UUID,
+	tx *sql.Tx,
+) (map[uuid.UUID]string, error) {
+	result := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := `SELECT id, type FROM aggregates WHERE id IN (?` + strings.Repeat(`, ?`, len(ids)-1) + `)`
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var aggregateType string
+		if err = rows.Scan(&id, &aggregateType); err != nil {
+			return nil, err
+		}
+		result[id] = aggregateType
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *SQLiteDB[T, S]) CountTransactions(
+	ctx context.Context,
+	afterSequenceID int64,
+	tx *sql.Tx,
+) (int64, error) {
+	query := `SELECT count(*) FROM transactions WHERE sequence_id > ?`
+	var count int64
+	err := tx.QueryRowContext(ctx, query, afterSequenceID).Scan(&count)
+	return count, err
+}
+
+func (db *SQLiteDB[T, S]) HasCommand(
+	ctx context.Context,
+	commandID string,
+	since time.Time,
+	tx *sql.Tx,
+) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM transactions WHERE command_id = ? AND created_at >= ?)`
+	var exists bool
+	err := tx.QueryRowContext(ctx, query, commandID, since.UnixMicro()).Scan(&exists)
+	return exists, err
+}
+
+func (db *SQLiteDB[T, S]) PurgeCommands(
+	ctx context.Context,
+	before time.Time,
+	tx *sql.Tx,
+) (int64, error) {
+	query := `UPDATE transactions SET command_id = NULL WHERE command_id IS NOT NULL AND created_at < ?`
+	result, err := tx.ExecContext(ctx, query, before.UnixMicro())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (db *SQLiteDB[T, S]) GetSubscription(
+	ctx context.Context,
+	name string,
+	tx *sql.Tx,
+) (*subscriptions.Subscription, error) {
+	insertQuery := `INSERT INTO subscriptions (name, last_sequence_id, updated_at) VALUES (?, 0, ?) ON CONFLICT (name) DO NOTHING` //nolint:lll
+	query := `SELECT last_sequence_id FROM subscriptions WHERE name = ?`
+	_, err := tx.ExecContext(ctx, insertQuery, name, time.Now().UnixMicro())
+	if err != nil {
+		return nil, err
+	}
+	var lastSequenceID int64
+	err = tx.QueryRowContext(ctx, query, name).Scan(&lastSequenceID)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions.NewSubscription(name, lastSequenceID), nil
+}
+
+func (db *SQLiteDB[T, S]) GetSubscriptions(
+	ctx context.Context,
+	tx *sql.Tx,
+) ([]*subscriptions.Subscription, error) {
+	query := `SELECT name, last_sequence_id FROM subscriptions ORDER BY name`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*subscriptions.Subscription, 0)
+	for rows.Next() {
+		var name string
+		var lastSequenceID int64
+		if err = rows.Scan(&name, &lastSequenceID); err != nil {
+			return nil, err
+		}
+		result = append(result, subscriptions.NewSubscription(name, lastSequenceID))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (db *SQLiteDB[T, S]) UpdateSubscription(
+	ctx context.Context,
+	sub *subscriptions.Subscription,
+	tx *sql.Tx,
+) error {
+	query := `UPDATE subscriptions SET last_sequence_id = ?, updated_at = ? WHERE name = ?`
+	_, err := tx.ExecContext(ctx, query, sub.LastSequenceID, time.Now().UnixMicro(), sub.Name)
+	return err
+}
+
+func (db *SQLiteDB[T, S]) ResetSubscription(
+	ctx context.Context,
+	name string,
+	lastSequenceID int64,
+	tx *sql.Tx,
+) error {
+	query := `INSERT INTO subscriptions (name, last_sequence_id, updated_at) VALUES (?, ?, ?) ON CONFLICT (name) DO UPDATE SET last_sequence_id = excluded.last_sequence_id, updated_at = excluded.updated_at` //nolint:lll
+	_, err := tx.ExecContext(ctx, query, name, lastSequenceID, time.Now().UnixMicro())
+	return err
+}
+
+func (db *SQLiteDB[T, S]) createVersion(
+	ctx context.Context,
+	id uuid.UUID,
+	aggregateType string,
+	version int,
+	tx *sql.Tx,
+) error {
+	query := `INSERT INTO aggregates (id, version, type) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING`
+	res, err := tx.ExecContext(ctx, query, id, version, aggregateType)
+	if err != nil {
+		return err
+	}
+	return db.checkAffected(ctx, res, id, 0, tx)
+}
+
+func (db *SQLiteDB[T, S]) updateVersion(
+	ctx context.Context,
+	id uuid.UUID,
+	aggregateType string,
+	currentVersion, nextVersion int,
+	tx *sql.Tx,
+) error {
+	query := `UPDATE aggregates SET version = ?, type = COALESCE(NULLIF(type, ''), ?) WHERE id = ? AND version = ?`
+	res, err := tx.ExecContext(ctx, query, nextVersion, aggregateType, id, currentVersion)
+	if err != nil {
+		return err
+	}
+	return db.checkAffected(ctx, res, id, currentVersion, tx)
+}
+
+func (db *SQLiteDB[T, S]) checkAffected(
+	ctx context.Context,
+	res sql.Result,
+	id uuid.UUID,
+	expectedVersion int,
+	tx *sql.Tx,
+) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	var actualVersion int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM aggregates WHERE id = ?`, id).Scan(&actualVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return repositories.NewConcurrencyConflictError(id, expectedVersion, actualVersion)
+}
+
+func (db *SQLiteDB[T, S]) insertEvents(
+	ctx context.Context,
+	events []events.Event[T],
+	createdAt int64,
+	tx *sql.Tx,
+) (err error) {
+	query := `INSERT INTO events (aggregate_id, transaction_id, version, command_type, event_type, payload, metadata, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)` //nolint:lll
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := stmt.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for _, event := range events {
+		payload, errMarshal := json.Marshal(event.Payload)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		metadata, errMarshal := json.Marshal(event.Metadata)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		_, err = stmt.ExecContext(
+			ctx,
+			event.AggregateID,
+			event.TransactionID,
+			event.Version,
+			event.CommandType,
+			event.Type,
+			string(payload),
+			string(metadata),
+			createdAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *SQLiteDB[T, S]) insertTransaction(
+	ctx context.Context,
+	transaction *transactions.Transaction,
+	createdAt int64,
+	tx *sql.Tx,
+) error {
+	query := `INSERT INTO transactions (id, aggregate_id, command_id, created_at) VALUES (?, ?, NULLIF(?, ''), ?)`
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		transaction.ID,
+		transaction.AggregateID,
+		transaction.CommandID,
+		createdAt,
+	)
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) &&
+		sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE &&
+		strings.Contains(sqliteErr.Error(), commandIDConstraint) {
+		return repositories.ErrDuplicateCommand
+	}
+	return err
+}
+
+func (db *SQLiteDB[T, S]) insertSnapshot(
+	ctx context.Context,
+	aggregateID uuid.UUID,
+	version int,
+	payload S,
+	tx *sql.Tx,
+) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO snapshots (aggregate_id, version, payload) VALUES (?, ?, ?)`
+	_, err = tx.ExecContext(ctx, query, aggregateID, version, string(raw))
+	return err
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryTransactions(
+	ctx context.Context,
+	q queryer,
+	afterSequenceID int64,
+	limit int,
+) ([]*transactions.Transaction, error) {
+	query := `SELECT t.id, t.aggregate_id, COALESCE(a.type, ''), t.sequence_id, COALESCE(t.command_id, '') FROM transactions AS t LEFT JOIN aggregates AS a ON a.id = t.aggregate_id WHERE t.sequence_id > ? ORDER BY t.sequence_id LIMIT ?` //nolint:lll
+	rows, err := q.QueryContext(ctx, query, afterSequenceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*transactions.Transaction, 0, limit)
+	for rows.Next() {
+		var id, aggregateID uuid.UUID
+		var sequenceID int64
+		var aggregateType, commandID string
+		if err = rows.Scan(&id, &aggregateID, &aggregateType, &sequenceID, &commandID); err != nil {
+			return nil, err
+		}
+		transaction := transactions.NewTransaction(id, aggregateID, sequenceID)
+		transaction.AggregateType = aggregateType
+		transaction.CommandID = commandID
+		result = append(result, transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func querySnapshot[S any](ctx context.Context, tx *sql.Tx, query string, args ...any) (int, S, error) {
+	var payload S
+	var version int
+	var raw string
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&version, &raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, payload, nil
+		}
+		return 0, payload, err
+	}
+	if err = json.Unmarshal([]byte(raw), &payload); err != nil {
+		return 0, payload, err
+	}
+	return version, payload, nil
+}
+
+func queryEvents[T any](ctx context.Context, tx *sql.Tx, query string, args ...any) ([]events.Event[T], error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]events.Event[T], 0)
+	for rows.Next() {
+		event, errScan := scanEvent[T](rows)
+		if errScan != nil {
+			return nil, errScan
+		}
+		result = append(result, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func scanEvent[T any](rows *sql.Rows, dest ...any) (events.Event[T], error) {
+	var event events.Event[T]
+	var payload, metadata string
+	var createdAt int64
+	err := rows.Scan(append(
+		dest,
+		&event.AggregateID,
+		&event.TransactionID,
+		&event.Version,
+		&event.CommandType,
+		&event.Type,
+		&payload,
+		&metadata,
+		&createdAt,
+	)...)
+	if err != nil {
+		return event, err
+	}
+	if err = json.Unmarshal([]byte(payload), &event.Payload); err != nil {
+		return event, err
+	}
+	if err = json.Unmarshal([]byte(metadata), &event.Metadata); err != nil {
+		return event, err
+	}
+	created := time.UnixMicro(createdAt)
+	event.CreatedAt = &created
+	return event, nil
+}
@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+)
+
+const (
+	DefaultFeedInterval  = 500 * time.Millisecond
+	DefaultFeedBatchSize = 100
+	shutdownPollInterval = 10 * time.Millisecond
+)
+
+type FeedOption func(*Feed)
+
+func WithFeedInterval(interval time.Duration) FeedOption {
+	return func(f *Feed) {
+		f.interval = interval
+	}
+}
+
+func WithFeedBatchSize(size int) FeedOption {
+	return func(f *Feed) {
+		f.batchSize = size
+	}
+}
+
+func WithFeedSubscription(name string) FeedOption {
+	return func(f *Feed) {
+		f.subscription = name
+	}
+}
+
+type Feed struct {
+	db           *sql.DB
+	handle       func(context.Context, *transactions.Transaction) error
+	baseContext  func() context.Context
+	interval     time.Duration
+	batchSize    int
+	subscription string
+	position     int64
+	inShutdown   atomic.Bool
+	log          *slog.Logger
+}
+
+func NewFeed(
+	db *sql.DB,
+	handle func(context.Context, *transactions.Transaction) error,
+	baseContext func() context.Context,
+	log *slog.Logger,
+	opts ...FeedOption,
+) *Feed {
+	f := &Feed{
+		db:          db,
+		handle:      handle,
+		baseContext: baseContext,
+		interval:    DefaultFeedInterval,
+		batchSize:   DefaultFeedBatchSize,
+		log:         log,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *Feed) StartFeed() error {
+	ctx := f.baseContext()
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		if err := f.poll(ctx); err != nil && ctx.Err() == nil {
+			f.log.ErrorContext(ctx, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			f.inShutdown.Store(true)
+			return http.ErrServerClosed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Feed) Shutdown(ctx context.Context) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		if f.inShutdown.Load() {
+			f.log.InfoContext(ctx, "sqlite feed shutting down successfully")
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			f.log.ErrorContext(ctx, ctx.Err().Error())
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Feed) poll(ctx context.Context) error {
+	if err := f.seed(ctx); err != nil {
+		return err
+	}
+	for {
+		batch, err := queryTransactions(ctx, f.db, f.position, f.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range batch {
+			if err = f.handle(ctx, transaction); err != nil {
+				return err
+			}
+			f.position = transaction.SequenceID
+		}
+		if len(batch) < f.batchSize {
+			return nil
+		}
+	}
+}
+
+func (f *Feed) seed(ctx context.Context) error {
+	if f.subscription == "" {
+		return nil
+	}
+	query := `SELECT last_sequence_id FROM subscriptions WHERE name = ?`
+	var lastSequenceID int64
+	err := f.db.QueryRowContext(ctx, query, f.subscription).Scan(&lastSequenceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.position = max(f.position, lastSequenceID)
+	return nil
+}
@@ -0,0 +1,181 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/entities"
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
+	"github.com/alex-fullstack/event-sourcingo/domain/transactions"
+	"github.com/alex-fullstack/event-sourcingo/infrastructure/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	feedWaitTimeout  = 2 * time.Second
+	feedSubscription = "feed-test"
+)
+
+type FeedTestCase struct {
+	description       string
+	transactions      int
+	opts              []sqlite.FeedOption
+	checkpoint        int64
+	failures          map[int64]int
+	expectedSequences []int64
+}
+
+func TestFeed_StartFeedMethod(t *testing.T) {
+	errExpected := errors.New("test error")
+	testCases := []FeedTestCase{
+		{
+			description:       "Лента должна доставлять все транзакции по порядку, загружая их пачками",
+			transactions:      5,
+			opts:              []sqlite.FeedOption{sqlite.WithFeedBatchSize(2), sqlite.WithFeedInterval(time.Hour)},
+			expectedSequences: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			description:       "Если обработка транзакции завершилась ошибкой, то лента должна повторить ее при следующем опросе", //nolint:lll
+			transactions:      3,
+			opts:              []sqlite.FeedOption{sqlite.WithFeedInterval(10 * time.Millisecond)},
+			failures:          map[int64]int{2: 1},
+			expectedSequences: []int64{1, 2, 2, 3},
+		},
+		{
+			description:       "Лента с подпиской должна начинать чтение с сохраненной позиции подписки, а не с начала журнала", //nolint:lll
+			transactions:      4,
+			opts:              []sqlite.FeedOption{sqlite.WithFeedSubscription(feedSubscription)},
+			checkpoint:        2,
+			expectedSequences: []int64{3, 4},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(
+			tc.description,
+			func(t *testing.T) {
+				store := newStore(t)
+				writeTransactions(t, store, tc.transactions)
+				if tc.checkpoint > 0 {
+					saveCheckpoint(t, store, tc.checkpoint)
+				}
+
+				var (
+					mu        sync.Mutex
+					delivered []int64
+					done      = make(chan struct{})
+				)
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				feed := sqlite.NewFeed(
+					store.DB(),
+					func(_ context.Context, transaction *transactions.Transaction) error {
+						mu.Lock()
+						defer mu.Unlock()
+						delivered = append(delivered, transaction.SequenceID)
+						if len(delivered) == len(tc.expectedSequences) {
+							close(done)
+						}
+						if tc.failures[transaction.SequenceID] > 0 {
+							tc.failures[transaction.SequenceID]--
+							return errExpected
+						}
+						return nil
+					},
+					func() context.Context {
+						return ctx
+					},
+					slog.Default(),
+					tc.opts...,
+				)
+				result := make(chan error, 1)
+				go func() {
+					result <- feed.StartFeed()
+				}()
+				select {
+				case <-done:
+				case <-time.After(feedWaitTimeout):
+					require.FailNow(t, "feed did not deliver the expected transactions")
+				}
+				cancel()
+
+				shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), feedWaitTimeout)
+				defer shutdownCancel()
+				require.NoError(t, feed.Shutdown(shutdownCtx))
+				assert.ErrorIs(t, <-result, http.ErrServerClosed)
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(t, tc.expectedSequences, delivered)
+			},
+		)
+	}
+}
+
+func TestFeed_ShutdownMethod(t *testing.T) {
+	store := newStore(t)
+	feed := sqlite.NewFeed(
+		store.DB(),
+		func(context.Context, *transactions.Transaction) error {
+			return nil
+		},
+		context.Background,
+		slog.Default(),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, feed.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func newStore(t *testing.T) *sqlite.SQLiteDB[int, int] {
+	t.Helper()
+	store, err := sqlite.NewSQLiteDB[int, int](
+		context.Background(),
+		"file:"+filepath.Join(t.TempDir(), "events.db"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+	return store
+}
+
+func writeTransactions(t *testing.T, store *sqlite.SQLiteDB[int, int], count int) {
+	t.Helper()
+	ctx := context.Background()
+	for i := range count {
+		id := uuid.New()
+		transaction := transactions.NewTransaction(uuid.New(), id, 0)
+		aggregate := entities.NewAggregate[int, int](
+			id,
+			10,
+			func(events.Event[int]) error { return nil },
+			func(int) error { return nil },
+		)
+		require.NoError(t, aggregate.ApplyChange(events.NewEvent(id, transaction.ID, 1, 1, 1, i)))
+
+		tx, err := store.Begin(ctx)
+		require.NoError(t, err)
+		require.NoError(t, store.UpdateOrCreateAggregate(ctx, transaction, aggregate, 0, tx))
+		require.NoError(t, store.Commit(ctx, tx))
+	}
+}
+
+func saveCheckpoint(t *testing.T, store *sqlite.SQLiteDB[int, int], sequenceID int64) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := store.Begin(ctx)
+	require.NoError(t, err)
+	_, err = store.GetSubscription(ctx, feedSubscription, tx)
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateSubscription(ctx, subscriptions.NewSubscription(feedSubscription, sequenceID), tx))
+	require.NoError(t, store.Commit(ctx, tx))
+}
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS aggregates (
+    id TEXT PRIMARY KEY,
+    version INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    sequence_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    aggregate_id TEXT NOT NULL,
+    command_id TEXT,
+    created_at INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_command_id_idx ON transactions (command_id) WHERE command_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS transactions_aggregate_id_idx ON transactions (aggregate_id, sequence_id);
+
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    aggregate_id TEXT NOT NULL,
+    transaction_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    command_type INTEGER NOT NULL,
+    event_type INTEGER NOT NULL,
+    payload TEXT NOT NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    created_at INTEGER NOT NULL,
+    UNIQUE (aggregate_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS events_transaction_id_idx ON events (transaction_id);
+
+CREATE TABLE IF NOT EXISTS snapshots (
+    aggregate_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    payload TEXT NOT NULL,
+    PRIMARY KEY (aggregate_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    name TEXT PRIMARY KEY,
+    last_sequence_id INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE aggregates ADD COLUMN type TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+	"strings"
+
+	"github.com/alex-fullstack/event-sourcingo/domain/events"
+	"github.com/alex-fullstack/event-sourcingo/domain/usecases/repositories"
+)
+
+func (db *SQLiteDB[T, S]) ReadAll(
+	ctx context.Context,
+	fromPosition events.Position,
+	limit int,
+	filter events.Filter,
+	tx *sql.Tx,
+) iter.Seq2[events.StreamEvent[T], error] {
+	return repositories.ReadPages(
+		fromPosition,
+		limit,
+		func(after events.Position, pageSize int) ([]events.StreamEvent[T], error) {
+			query, args := buildReadAllQuery(after, pageSize, filter)
+			rows, err := tx.QueryContext(ctx, query, args...)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			result := make([]events.StreamEvent[T], 0, pageSize)
+			for rows.Next() {
+				var streamEvent events.StreamEvent[T]
+				streamEvent.Event, err = scanEvent[T](
+					rows,
+					&streamEvent.Position.SequenceID,
+					&streamEvent.AggregateType,
+				)
+				if err != nil {
+					return nil, err
+				}
+				streamEvent.Position.Version = streamEvent.Version
+				result = append(result, streamEvent)
+			}
+			if err = rows.Err(); err != nil {
+				return nil, err
+			}
+			return result, nil
+		},
+	)
+}
+
+func buildReadAllQuery(after events.Position, limit int, filter events.Filter) (string, []any) {
+	var sb strings.Builder
+	sb.WriteString(`SELECT t.sequence_id, COALESCE(a.type, ''), e.aggregate_id, e.transaction_id, e.version, e.command_type, e.event_type, e.payload, e.metadata, e.created_at FROM transactions AS t JOIN events AS e ON e.transaction_id = t.id LEFT JOIN aggregates AS a ON a.id = t.aggregate_id WHERE (t.sequence_id > ? OR t.sequence_id = ? AND e.version > ?)`) //nolint:lll
+	args := []any{after.SequenceID, after.SequenceID, after.Version}
+	args = writeIn(&sb, "e.aggregate_id", filter.AggregateIDs, args)
+	args = writeIn(&sb, "e.event_type", filter.EventTypes, args)
+	args = writeIn(&sb, "e.command_type", filter.CommandTypes, args)
+	args = writeIn(&sb, "COALESCE(a.type, '')", filter.AggregateTypes, args)
+	sb.WriteString(` ORDER BY t.sequence_id, e.version LIMIT ?`)
+	return sb.String(), append(args, limit)
+}
+
+func writeIn[V any](sb *strings.Builder, column string, values []V, args []any) []any {
+	if len(values) == 0 {
+		return args
+	}
+	sb.WriteString(` AND ` + column + ` IN (?` + strings.Repeat(`, ?`, len(values)-1) + `)`)
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return args
+}
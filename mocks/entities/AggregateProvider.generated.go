@@ -531,6 +531,51 @@ func (_c *MockAggregateProvider_Snapshot_Call[T, S, P, K]) RunAndReturn(run func
 	return _c
 }
 
+// Type provides a mock function with no fields
+func (_m *MockAggregateProvider[T, S, P, K]) Type() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Type")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockAggregateProvider_Type_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Type'
+type MockAggregateProvider_Type_Call[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	*mock.Call
+}
+
+// Type is a helper method to define mock.On call
+func (_e *MockAggregateProvider_Expecter[T, S, P, K]) Type() *MockAggregateProvider_Type_Call[T, S, P, K] {
+	return &MockAggregateProvider_Type_Call[T, S, P, K]{Call: _e.mock.On("Type")}
+}
+
+func (_c *MockAggregateProvider_Type_Call[T, S, P, K]) Run(run func()) *MockAggregateProvider_Type_Call[T, S, P, K] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockAggregateProvider_Type_Call[T, S, P, K]) Return(_a0 string) *MockAggregateProvider_Type_Call[T, S, P, K] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAggregateProvider_Type_Call[T, S, P, K]) RunAndReturn(run func() string) *MockAggregateProvider_Type_Call[T, S, P, K] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Version provides a mock function with no fields
 func (_m *MockAggregateProvider[T, S, P, K]) Version() int {
 	ret := _m.Called()
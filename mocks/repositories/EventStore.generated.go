@@ -8,10 +8,16 @@ import (
 	entities "github.com/alex-fullstack/event-sourcingo/domain/entities"
 	events "github.com/alex-fullstack/event-sourcingo/domain/events"
 
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
 
 	subscriptions "github.com/alex-fullstack/event-sourcingo/domain/subscriptions"
 
+	time "time"
+
+	transactions "github.com/alex-fullstack/event-sourcingo/domain/transactions"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -133,6 +139,186 @@ func (_c *MockEventStore_Commit_Call[T, S, E]) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// CountTransactions provides a mock function with given fields: ctx, afterSequenceID, executor
+func (_m *MockEventStore[T, S, E]) CountTransactions(ctx context.Context, afterSequenceID int64, executor E) (int64, error) {
+	ret := _m.Called(ctx, afterSequenceID, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTransactions")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, E) (int64, error)); ok {
+		return rf(ctx, afterSequenceID, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, E) int64); ok {
+		r0 = rf(ctx, afterSequenceID, executor)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, E) error); ok {
+		r1 = rf(ctx, afterSequenceID, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_CountTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountTransactions'
+type MockEventStore_CountTransactions_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// CountTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterSequenceID int64
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) CountTransactions(ctx interface{}, afterSequenceID interface{}, executor interface{}) *MockEventStore_CountTransactions_Call[T, S, E] {
+	return &MockEventStore_CountTransactions_Call[T, S, E]{Call: _e.mock.On("CountTransactions", ctx, afterSequenceID, executor)}
+}
+
+func (_c *MockEventStore_CountTransactions_Call[T, S, E]) Run(run func(ctx context.Context, afterSequenceID int64, executor E)) *MockEventStore_CountTransactions_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_CountTransactions_Call[T, S, E]) Return(_a0 int64, _a1 error) *MockEventStore_CountTransactions_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_CountTransactions_Call[T, S, E]) RunAndReturn(run func(context.Context, int64, E) (int64, error)) *MockEventStore_CountTransactions_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAggregateIDs provides a mock function with given fields: ctx, afterID, limit, aggregateType, executor
+func (_m *MockEventStore[T, S, E]) GetAggregateIDs(ctx context.Context, afterID uuid.UUID, limit int, aggregateType string, executor E) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, afterID, limit, aggregateType, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAggregateIDs")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, string, E) ([]uuid.UUID, error)); ok {
+		return rf(ctx, afterID, limit, aggregateType, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, string, E) []uuid.UUID); ok {
+		r0 = rf(ctx, afterID, limit, aggregateType, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, string, E) error); ok {
+		r1 = rf(ctx, afterID, limit, aggregateType, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_GetAggregateIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAggregateIDs'
+type MockEventStore_GetAggregateIDs_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetAggregateIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID uuid.UUID
+//   - limit int
+//   - aggregateType string
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetAggregateIDs(ctx interface{}, afterID interface{}, limit interface{}, aggregateType interface{}, executor interface{}) *MockEventStore_GetAggregateIDs_Call[T, S, E] {
+	return &MockEventStore_GetAggregateIDs_Call[T, S, E]{Call: _e.mock.On("GetAggregateIDs", ctx, afterID, limit, aggregateType, executor)}
+}
+
+func (_c *MockEventStore_GetAggregateIDs_Call[T, S, E]) Run(run func(ctx context.Context, afterID uuid.UUID, limit int, aggregateType string, executor E)) *MockEventStore_GetAggregateIDs_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(string), args[4].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetAggregateIDs_Call[T, S, E]) Return(_a0 []uuid.UUID, _a1 error) *MockEventStore_GetAggregateIDs_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_GetAggregateIDs_Call[T, S, E]) RunAndReturn(run func(context.Context, uuid.UUID, int, string, E) ([]uuid.UUID, error)) *MockEventStore_GetAggregateIDs_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAggregateTypes provides a mock function with given fields: ctx, ids, executor
+func (_m *MockEventStore[T, S, E]) GetAggregateTypes(ctx context.Context, ids []uuid.UUID, executor E) (map[uuid.UUID]string, error) {
+	ret := _m.Called(ctx, ids, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAggregateTypes")
+	}
+
+	var r0 map[uuid.UUID]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, E) (map[uuid.UUID]string, error)); ok {
+		return rf(ctx, ids, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, E) map[uuid.UUID]string); ok {
+		r0 = rf(ctx, ids, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID, E) error); ok {
+		r1 = rf(ctx, ids, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_GetAggregateTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAggregateTypes'
+type MockEventStore_GetAggregateTypes_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetAggregateTypes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetAggregateTypes(ctx interface{}, ids interface{}, executor interface{}) *MockEventStore_GetAggregateTypes_Call[T, S, E] {
+	return &MockEventStore_GetAggregateTypes_Call[T, S, E]{Call: _e.mock.On("GetAggregateTypes", ctx, ids, executor)}
+}
+
+func (_c *MockEventStore_GetAggregateTypes_Call[T, S, E]) Run(run func(ctx context.Context, ids []uuid.UUID, executor E)) *MockEventStore_GetAggregateTypes_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uuid.UUID), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetAggregateTypes_Call[T, S, E]) Return(_a0 map[uuid.UUID]string, _a1 error) *MockEventStore_GetAggregateTypes_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_GetAggregateTypes_Call[T, S, E]) RunAndReturn(run func(context.Context, []uuid.UUID, E) (map[uuid.UUID]string, error)) *MockEventStore_GetAggregateTypes_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetEvents provides a mock function with given fields: ctx, id, fromVersion, toVersion, executor
 func (_m *MockEventStore[T, S, E]) GetEvents(ctx context.Context, id uuid.UUID, fromVersion int, toVersion *int, executor E) ([]events.Event[T], error) {
 	ret := _m.Called(ctx, id, fromVersion, toVersion, executor)
@@ -195,6 +381,68 @@ func (_c *MockEventStore_GetEvents_Call[T, S, E]) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetEventsUntil provides a mock function with given fields: ctx, id, fromVersion, until, executor
+func (_m *MockEventStore[T, S, E]) GetEventsUntil(ctx context.Context, id uuid.UUID, fromVersion int, until time.Time, executor E) ([]events.Event[T], error) {
+	ret := _m.Called(ctx, id, fromVersion, until, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventsUntil")
+	}
+
+	var r0 []events.Event[T]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, time.Time, E) ([]events.Event[T], error)); ok {
+		return rf(ctx, id, fromVersion, until, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, time.Time, E) []events.Event[T]); ok {
+		r0 = rf(ctx, id, fromVersion, until, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]events.Event[T])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, time.Time, E) error); ok {
+		r1 = rf(ctx, id, fromVersion, until, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_GetEventsUntil_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventsUntil'
+type MockEventStore_GetEventsUntil_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetEventsUntil is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - fromVersion int
+//   - until time.Time
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetEventsUntil(ctx interface{}, id interface{}, fromVersion interface{}, until interface{}, executor interface{}) *MockEventStore_GetEventsUntil_Call[T, S, E] {
+	return &MockEventStore_GetEventsUntil_Call[T, S, E]{Call: _e.mock.On("GetEventsUntil", ctx, id, fromVersion, until, executor)}
+}
+
+func (_c *MockEventStore_GetEventsUntil_Call[T, S, E]) Run(run func(ctx context.Context, id uuid.UUID, fromVersion int, until time.Time, executor E)) *MockEventStore_GetEventsUntil_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int), args[3].(time.Time), args[4].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetEventsUntil_Call[T, S, E]) Return(_a0 []events.Event[T], _a1 error) *MockEventStore_GetEventsUntil_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_GetEventsUntil_Call[T, S, E]) RunAndReturn(run func(context.Context, uuid.UUID, int, time.Time, E) ([]events.Event[T], error)) *MockEventStore_GetEventsUntil_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSnapshot provides a mock function with given fields: ctx, id, versionAfter, executor
 func (_m *MockEventStore[T, S, E]) GetSnapshot(ctx context.Context, id uuid.UUID, versionAfter *int, executor E) (int, S, error) {
 	ret := _m.Called(ctx, id, versionAfter, executor)
@@ -263,9 +511,77 @@ func (_c *MockEventStore_GetSnapshot_Call[T, S, E]) RunAndReturn(run func(contex
 	return _c
 }
 
-// GetSubscription provides a mock function with given fields: ctx, executor
-func (_m *MockEventStore[T, S, E]) GetSubscription(ctx context.Context, executor E) (*subscriptions.Subscription, error) {
-	ret := _m.Called(ctx, executor)
+// GetSnapshotAsOf provides a mock function with given fields: ctx, id, asOf, executor
+func (_m *MockEventStore[T, S, E]) GetSnapshotAsOf(ctx context.Context, id uuid.UUID, asOf time.Time, executor E) (int, S, error) {
+	ret := _m.Called(ctx, id, asOf, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSnapshotAsOf")
+	}
+
+	var r0 int
+	var r1 S
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, E) (int, S, error)); ok {
+		return rf(ctx, id, asOf, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, E) int); ok {
+		r0 = rf(ctx, id, asOf, executor)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, E) S); ok {
+		r1 = rf(ctx, id, asOf, executor)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(S)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, time.Time, E) error); ok {
+		r2 = rf(ctx, id, asOf, executor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockEventStore_GetSnapshotAsOf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSnapshotAsOf'
+type MockEventStore_GetSnapshotAsOf_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetSnapshotAsOf is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - asOf time.Time
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetSnapshotAsOf(ctx interface{}, id interface{}, asOf interface{}, executor interface{}) *MockEventStore_GetSnapshotAsOf_Call[T, S, E] {
+	return &MockEventStore_GetSnapshotAsOf_Call[T, S, E]{Call: _e.mock.On("GetSnapshotAsOf", ctx, id, asOf, executor)}
+}
+
+func (_c *MockEventStore_GetSnapshotAsOf_Call[T, S, E]) Run(run func(ctx context.Context, id uuid.UUID, asOf time.Time, executor E)) *MockEventStore_GetSnapshotAsOf_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetSnapshotAsOf_Call[T, S, E]) Return(_a0 int, _a1 S, _a2 error) *MockEventStore_GetSnapshotAsOf_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockEventStore_GetSnapshotAsOf_Call[T, S, E]) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, E) (int, S, error)) *MockEventStore_GetSnapshotAsOf_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSubscription provides a mock function with given fields: ctx, name, executor
+func (_m *MockEventStore[T, S, E]) GetSubscription(ctx context.Context, name string, executor E) (*subscriptions.Subscription, error) {
+	ret := _m.Called(ctx, name, executor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSubscription")
@@ -273,19 +589,19 @@ func (_m *MockEventStore[T, S, E]) GetSubscription(ctx context.Context, executor
 
 	var r0 *subscriptions.Subscription
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, E) (*subscriptions.Subscription, error)); ok {
-		return rf(ctx, executor)
+	if rf, ok := ret.Get(0).(func(context.Context, string, E) (*subscriptions.Subscription, error)); ok {
+		return rf(ctx, name, executor)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, E) *subscriptions.Subscription); ok {
-		r0 = rf(ctx, executor)
+	if rf, ok := ret.Get(0).(func(context.Context, string, E) *subscriptions.Subscription); ok {
+		r0 = rf(ctx, name, executor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*subscriptions.Subscription)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, E) error); ok {
-		r1 = rf(ctx, executor)
+	if rf, ok := ret.Get(1).(func(context.Context, string, E) error); ok {
+		r1 = rf(ctx, name, executor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -300,14 +616,15 @@ type MockEventStore_GetSubscription_Call[T interface{}, S interface{}, E interfa
 
 // GetSubscription is a helper method to define mock.On call
 //   - ctx context.Context
+//   - name string
 //   - executor E
-func (_e *MockEventStore_Expecter[T, S, E]) GetSubscription(ctx interface{}, executor interface{}) *MockEventStore_GetSubscription_Call[T, S, E] {
-	return &MockEventStore_GetSubscription_Call[T, S, E]{Call: _e.mock.On("GetSubscription", ctx, executor)}
+func (_e *MockEventStore_Expecter[T, S, E]) GetSubscription(ctx interface{}, name interface{}, executor interface{}) *MockEventStore_GetSubscription_Call[T, S, E] {
+	return &MockEventStore_GetSubscription_Call[T, S, E]{Call: _e.mock.On("GetSubscription", ctx, name, executor)}
 }
 
-func (_c *MockEventStore_GetSubscription_Call[T, S, E]) Run(run func(ctx context.Context, executor E)) *MockEventStore_GetSubscription_Call[T, S, E] {
+func (_c *MockEventStore_GetSubscription_Call[T, S, E]) Run(run func(ctx context.Context, name string, executor E)) *MockEventStore_GetSubscription_Call[T, S, E] {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(E))
+		run(args[0].(context.Context), args[1].(string), args[2].(E))
 	})
 	return _c
 }
@@ -317,7 +634,127 @@ func (_c *MockEventStore_GetSubscription_Call[T, S, E]) Return(_a0 *subscription
 	return _c
 }
 
-func (_c *MockEventStore_GetSubscription_Call[T, S, E]) RunAndReturn(run func(context.Context, E) (*subscriptions.Subscription, error)) *MockEventStore_GetSubscription_Call[T, S, E] {
+func (_c *MockEventStore_GetSubscription_Call[T, S, E]) RunAndReturn(run func(context.Context, string, E) (*subscriptions.Subscription, error)) *MockEventStore_GetSubscription_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx, executor
+func (_m *MockEventStore[T, S, E]) GetSubscriptions(ctx context.Context, executor E) ([]*subscriptions.Subscription, error) {
+	ret := _m.Called(ctx, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptions")
+	}
+
+	var r0 []*subscriptions.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, E) ([]*subscriptions.Subscription, error)); ok {
+		return rf(ctx, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, E) []*subscriptions.Subscription); ok {
+		r0 = rf(ctx, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*subscriptions.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, E) error); ok {
+		r1 = rf(ctx, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_GetSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSubscriptions'
+type MockEventStore_GetSubscriptions_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetSubscriptions(ctx interface{}, executor interface{}) *MockEventStore_GetSubscriptions_Call[T, S, E] {
+	return &MockEventStore_GetSubscriptions_Call[T, S, E]{Call: _e.mock.On("GetSubscriptions", ctx, executor)}
+}
+
+func (_c *MockEventStore_GetSubscriptions_Call[T, S, E]) Run(run func(ctx context.Context, executor E)) *MockEventStore_GetSubscriptions_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetSubscriptions_Call[T, S, E]) Return(_a0 []*subscriptions.Subscription, _a1 error) *MockEventStore_GetSubscriptions_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_GetSubscriptions_Call[T, S, E]) RunAndReturn(run func(context.Context, E) ([]*subscriptions.Subscription, error)) *MockEventStore_GetSubscriptions_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactions provides a mock function with given fields: ctx, afterSequenceID, limit, executor
+func (_m *MockEventStore[T, S, E]) GetTransactions(ctx context.Context, afterSequenceID int64, limit int, executor E) ([]*transactions.Transaction, error) {
+	ret := _m.Called(ctx, afterSequenceID, limit, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactions")
+	}
+
+	var r0 []*transactions.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, E) ([]*transactions.Transaction, error)); ok {
+		return rf(ctx, afterSequenceID, limit, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, E) []*transactions.Transaction); ok {
+		r0 = rf(ctx, afterSequenceID, limit, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transactions.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, E) error); ok {
+		r1 = rf(ctx, afterSequenceID, limit, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_GetTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactions'
+type MockEventStore_GetTransactions_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterSequenceID int64
+//   - limit int
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) GetTransactions(ctx interface{}, afterSequenceID interface{}, limit interface{}, executor interface{}) *MockEventStore_GetTransactions_Call[T, S, E] {
+	return &MockEventStore_GetTransactions_Call[T, S, E]{Call: _e.mock.On("GetTransactions", ctx, afterSequenceID, limit, executor)}
+}
+
+func (_c *MockEventStore_GetTransactions_Call[T, S, E]) Run(run func(ctx context.Context, afterSequenceID int64, limit int, executor E)) *MockEventStore_GetTransactions_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_GetTransactions_Call[T, S, E]) Return(_a0 []*transactions.Transaction, _a1 error) *MockEventStore_GetTransactions_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_GetTransactions_Call[T, S, E]) RunAndReturn(run func(context.Context, int64, int, E) ([]*transactions.Transaction, error)) *MockEventStore_GetTransactions_Call[T, S, E] {
 	_c.Call.Return(run)
 	return _c
 }
@@ -384,6 +821,224 @@ func (_c *MockEventStore_GetUnhandledEvents_Call[T, S, E]) RunAndReturn(run func
 	return _c
 }
 
+// HasCommand provides a mock function with given fields: ctx, commandID, since, executor
+func (_m *MockEventStore[T, S, E]) HasCommand(ctx context.Context, commandID string, since time.Time, executor E) (bool, error) {
+	ret := _m.Called(ctx, commandID, since, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasCommand")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, E) (bool, error)); ok {
+		return rf(ctx, commandID, since, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, E) bool); ok {
+		r0 = rf(ctx, commandID, since, executor)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, E) error); ok {
+		r1 = rf(ctx, commandID, since, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_HasCommand_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasCommand'
+type MockEventStore_HasCommand_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// HasCommand is a helper method to define mock.On call
+//   - ctx context.Context
+//   - commandID string
+//   - since time.Time
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) HasCommand(ctx interface{}, commandID interface{}, since interface{}, executor interface{}) *MockEventStore_HasCommand_Call[T, S, E] {
+	return &MockEventStore_HasCommand_Call[T, S, E]{Call: _e.mock.On("HasCommand", ctx, commandID, since, executor)}
+}
+
+func (_c *MockEventStore_HasCommand_Call[T, S, E]) Run(run func(ctx context.Context, commandID string, since time.Time, executor E)) *MockEventStore_HasCommand_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_HasCommand_Call[T, S, E]) Return(_a0 bool, _a1 error) *MockEventStore_HasCommand_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_HasCommand_Call[T, S, E]) RunAndReturn(run func(context.Context, string, time.Time, E) (bool, error)) *MockEventStore_HasCommand_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeCommands provides a mock function with given fields: ctx, before, executor
+func (_m *MockEventStore[T, S, E]) PurgeCommands(ctx context.Context, before time.Time, executor E) (int64, error) {
+	ret := _m.Called(ctx, before, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeCommands")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, E) (int64, error)); ok {
+		return rf(ctx, before, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, E) int64); ok {
+		r0 = rf(ctx, before, executor)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, E) error); ok {
+		r1 = rf(ctx, before, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventStore_PurgeCommands_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeCommands'
+type MockEventStore_PurgeCommands_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// PurgeCommands is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) PurgeCommands(ctx interface{}, before interface{}, executor interface{}) *MockEventStore_PurgeCommands_Call[T, S, E] {
+	return &MockEventStore_PurgeCommands_Call[T, S, E]{Call: _e.mock.On("PurgeCommands", ctx, before, executor)}
+}
+
+func (_c *MockEventStore_PurgeCommands_Call[T, S, E]) Run(run func(ctx context.Context, before time.Time, executor E)) *MockEventStore_PurgeCommands_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_PurgeCommands_Call[T, S, E]) Return(_a0 int64, _a1 error) *MockEventStore_PurgeCommands_Call[T, S, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventStore_PurgeCommands_Call[T, S, E]) RunAndReturn(run func(context.Context, time.Time, E) (int64, error)) *MockEventStore_PurgeCommands_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadAll provides a mock function with given fields: ctx, fromPosition, limit, filter, executor
+func (_m *MockEventStore[T, S, E]) ReadAll(ctx context.Context, fromPosition events.Position, limit int, filter events.Filter, executor E) iter.Seq2[events.StreamEvent[T], error] {
+	ret := _m.Called(ctx, fromPosition, limit, filter, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadAll")
+	}
+
+	var r0 iter.Seq2[events.StreamEvent[T], error]
+	if rf, ok := ret.Get(0).(func(context.Context, events.Position, int, events.Filter, E) iter.Seq2[events.StreamEvent[T], error]); ok {
+		r0 = rf(ctx, fromPosition, limit, filter, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[events.StreamEvent[T], error])
+		}
+	}
+
+	return r0
+}
+
+// MockEventStore_ReadAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadAll'
+type MockEventStore_ReadAll_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// ReadAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fromPosition events.Position
+//   - limit int
+//   - filter events.Filter
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) ReadAll(ctx interface{}, fromPosition interface{}, limit interface{}, filter interface{}, executor interface{}) *MockEventStore_ReadAll_Call[T, S, E] {
+	return &MockEventStore_ReadAll_Call[T, S, E]{Call: _e.mock.On("ReadAll", ctx, fromPosition, limit, filter, executor)}
+}
+
+func (_c *MockEventStore_ReadAll_Call[T, S, E]) Run(run func(ctx context.Context, fromPosition events.Position, limit int, filter events.Filter, executor E)) *MockEventStore_ReadAll_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(events.Position), args[2].(int), args[3].(events.Filter), args[4].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_ReadAll_Call[T, S, E]) Return(_a0 iter.Seq2[events.StreamEvent[T], error]) *MockEventStore_ReadAll_Call[T, S, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventStore_ReadAll_Call[T, S, E]) RunAndReturn(run func(context.Context, events.Position, int, events.Filter, E) iter.Seq2[events.StreamEvent[T], error]) *MockEventStore_ReadAll_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetSubscription provides a mock function with given fields: ctx, name, lastSequenceID, executor
+func (_m *MockEventStore[T, S, E]) ResetSubscription(ctx context.Context, name string, lastSequenceID int64, executor E) error {
+	ret := _m.Called(ctx, name, lastSequenceID, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, E) error); ok {
+		r0 = rf(ctx, name, lastSequenceID, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEventStore_ResetSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetSubscription'
+type MockEventStore_ResetSubscription_Call[T interface{}, S interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// ResetSubscription is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - lastSequenceID int64
+//   - executor E
+func (_e *MockEventStore_Expecter[T, S, E]) ResetSubscription(ctx interface{}, name interface{}, lastSequenceID interface{}, executor interface{}) *MockEventStore_ResetSubscription_Call[T, S, E] {
+	return &MockEventStore_ResetSubscription_Call[T, S, E]{Call: _e.mock.On("ResetSubscription", ctx, name, lastSequenceID, executor)}
+}
+
+func (_c *MockEventStore_ResetSubscription_Call[T, S, E]) Run(run func(ctx context.Context, name string, lastSequenceID int64, executor E)) *MockEventStore_ResetSubscription_Call[T, S, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockEventStore_ResetSubscription_Call[T, S, E]) Return(_a0 error) *MockEventStore_ResetSubscription_Call[T, S, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventStore_ResetSubscription_Call[T, S, E]) RunAndReturn(run func(context.Context, string, int64, E) error) *MockEventStore_ResetSubscription_Call[T, S, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Rollback provides a mock function with given fields: ctx, executor
 func (_m *MockEventStore[T, S, E]) Rollback(ctx context.Context, executor E) error {
 	ret := _m.Called(ctx, executor)
@@ -431,17 +1086,17 @@ func (_c *MockEventStore_Rollback_Call[T, S, E]) RunAndReturn(run func(context.C
 	return _c
 }
 
-// UpdateOrCreateAggregate provides a mock function with given fields: ctx, transactionID, reader, snapshot, executor
-func (_m *MockEventStore[T, S, E]) UpdateOrCreateAggregate(ctx context.Context, transactionID uuid.UUID, reader entities.AggregateReader[T], snapshot S, executor E) error {
-	ret := _m.Called(ctx, transactionID, reader, snapshot, executor)
+// UpdateOrCreateAggregate provides a mock function with given fields: ctx, transaction, reader, snapshot, executor
+func (_m *MockEventStore[T, S, E]) UpdateOrCreateAggregate(ctx context.Context, transaction *transactions.Transaction, reader entities.AggregateReader[T], snapshot S, executor E) error {
+	ret := _m.Called(ctx, transaction, reader, snapshot, executor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateOrCreateAggregate")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, entities.AggregateReader[T], S, E) error); ok {
-		r0 = rf(ctx, transactionID, reader, snapshot, executor)
+	if rf, ok := ret.Get(0).(func(context.Context, *transactions.Transaction, entities.AggregateReader[T], S, E) error); ok {
+		r0 = rf(ctx, transaction, reader, snapshot, executor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -456,17 +1111,17 @@ type MockEventStore_UpdateOrCreateAggregate_Call[T interface{}, S interface{}, E
 
 // UpdateOrCreateAggregate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - transactionID uuid.UUID
+//   - transaction *transactions.Transaction
 //   - reader entities.AggregateReader[T]
 //   - snapshot S
 //   - executor E
-func (_e *MockEventStore_Expecter[T, S, E]) UpdateOrCreateAggregate(ctx interface{}, transactionID interface{}, reader interface{}, snapshot interface{}, executor interface{}) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
-	return &MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]{Call: _e.mock.On("UpdateOrCreateAggregate", ctx, transactionID, reader, snapshot, executor)}
+func (_e *MockEventStore_Expecter[T, S, E]) UpdateOrCreateAggregate(ctx interface{}, transaction interface{}, reader interface{}, snapshot interface{}, executor interface{}) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
+	return &MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]{Call: _e.mock.On("UpdateOrCreateAggregate", ctx, transaction, reader, snapshot, executor)}
 }
 
-func (_c *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]) Run(run func(ctx context.Context, transactionID uuid.UUID, reader entities.AggregateReader[T], snapshot S, executor E)) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
+func (_c *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]) Run(run func(ctx context.Context, transaction *transactions.Transaction, reader entities.AggregateReader[T], snapshot S, executor E)) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(entities.AggregateReader[T]), args[3].(S), args[4].(E))
+		run(args[0].(context.Context), args[1].(*transactions.Transaction), args[2].(entities.AggregateReader[T]), args[3].(S), args[4].(E))
 	})
 	return _c
 }
@@ -476,7 +1131,7 @@ func (_c *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]) Return(_a0 error
 	return _c
 }
 
-func (_c *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]) RunAndReturn(run func(context.Context, uuid.UUID, entities.AggregateReader[T], S, E) error) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
+func (_c *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E]) RunAndReturn(run func(context.Context, *transactions.Transaction, entities.AggregateReader[T], S, E) error) *MockEventStore_UpdateOrCreateAggregate_Call[T, S, E] {
 	_c.Call.Return(run)
 	return _c
 }
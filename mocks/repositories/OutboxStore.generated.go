@@ -0,0 +1,291 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	outbox "github.com/alex-fullstack/event-sourcingo/domain/outbox"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockOutboxStore is an autogenerated mock type for the OutboxStore type
+type MockOutboxStore[K interface{}, E interface{}] struct {
+	mock.Mock
+}
+
+type MockOutboxStore_Expecter[K interface{}, E interface{}] struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOutboxStore[K, E]) EXPECT() *MockOutboxStore_Expecter[K, E] {
+	return &MockOutboxStore_Expecter[K, E]{mock: &_m.Mock}
+}
+
+// Enqueue provides a mock function with given fields: ctx, messages, executor
+func (_m *MockOutboxStore[K, E]) Enqueue(ctx context.Context, messages []*outbox.Message[K], executor E) error {
+	ret := _m.Called(ctx, messages, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*outbox.Message[K], E) error); ok {
+		r0 = rf(ctx, messages, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxStore_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type MockOutboxStore_Enqueue_Call[K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// Enqueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []*outbox.Message[K]
+//   - executor E
+func (_e *MockOutboxStore_Expecter[K, E]) Enqueue(ctx interface{}, messages interface{}, executor interface{}) *MockOutboxStore_Enqueue_Call[K, E] {
+	return &MockOutboxStore_Enqueue_Call[K, E]{Call: _e.mock.On("Enqueue", ctx, messages, executor)}
+}
+
+func (_c *MockOutboxStore_Enqueue_Call[K, E]) Run(run func(ctx context.Context, messages []*outbox.Message[K], executor E)) *MockOutboxStore_Enqueue_Call[K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*outbox.Message[K]), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockOutboxStore_Enqueue_Call[K, E]) Return(_a0 error) *MockOutboxStore_Enqueue_Call[K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxStore_Enqueue_Call[K, E]) RunAndReturn(run func(context.Context, []*outbox.Message[K], E) error) *MockOutboxStore_Enqueue_Call[K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPending provides a mock function with given fields: ctx, limit, executor
+func (_m *MockOutboxStore[K, E]) GetPending(ctx context.Context, limit int, executor E) ([]*outbox.Message[K], error) {
+	ret := _m.Called(ctx, limit, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPending")
+	}
+
+	var r0 []*outbox.Message[K]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, E) ([]*outbox.Message[K], error)); ok {
+		return rf(ctx, limit, executor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, E) []*outbox.Message[K]); ok {
+		r0 = rf(ctx, limit, executor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*outbox.Message[K])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, E) error); ok {
+		r1 = rf(ctx, limit, executor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutboxStore_GetPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPending'
+type MockOutboxStore_GetPending_Call[K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// GetPending is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - executor E
+func (_e *MockOutboxStore_Expecter[K, E]) GetPending(ctx interface{}, limit interface{}, executor interface{}) *MockOutboxStore_GetPending_Call[K, E] {
+	return &MockOutboxStore_GetPending_Call[K, E]{Call: _e.mock.On("GetPending", ctx, limit, executor)}
+}
+
+func (_c *MockOutboxStore_GetPending_Call[K, E]) Run(run func(ctx context.Context, limit int, executor E)) *MockOutboxStore_GetPending_Call[K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockOutboxStore_GetPending_Call[K, E]) Return(_a0 []*outbox.Message[K], _a1 error) *MockOutboxStore_GetPending_Call[K, E] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutboxStore_GetPending_Call[K, E]) RunAndReturn(run func(context.Context, int, E) ([]*outbox.Message[K], error)) *MockOutboxStore_GetPending_Call[K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkFailed provides a mock function with given fields: ctx, ids, reason, executor
+func (_m *MockOutboxStore[K, E]) MarkFailed(ctx context.Context, ids []int64, reason string, executor E) error {
+	ret := _m.Called(ctx, ids, reason, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string, E) error); ok {
+		r0 = rf(ctx, ids, reason, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxStore_MarkFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkFailed'
+type MockOutboxStore_MarkFailed_Call[K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// MarkFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+//   - reason string
+//   - executor E
+func (_e *MockOutboxStore_Expecter[K, E]) MarkFailed(ctx interface{}, ids interface{}, reason interface{}, executor interface{}) *MockOutboxStore_MarkFailed_Call[K, E] {
+	return &MockOutboxStore_MarkFailed_Call[K, E]{Call: _e.mock.On("MarkFailed", ctx, ids, reason, executor)}
+}
+
+func (_c *MockOutboxStore_MarkFailed_Call[K, E]) Run(run func(ctx context.Context, ids []int64, reason string, executor E)) *MockOutboxStore_MarkFailed_Call[K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(string), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockOutboxStore_MarkFailed_Call[K, E]) Return(_a0 error) *MockOutboxStore_MarkFailed_Call[K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxStore_MarkFailed_Call[K, E]) RunAndReturn(run func(context.Context, []int64, string, E) error) *MockOutboxStore_MarkFailed_Call[K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkSent provides a mock function with given fields: ctx, ids, executor
+func (_m *MockOutboxStore[K, E]) MarkSent(ctx context.Context, ids []int64, executor E) error {
+	ret := _m.Called(ctx, ids, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, E) error); ok {
+		r0 = rf(ctx, ids, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxStore_MarkSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkSent'
+type MockOutboxStore_MarkSent_Call[K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// MarkSent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+//   - executor E
+func (_e *MockOutboxStore_Expecter[K, E]) MarkSent(ctx interface{}, ids interface{}, executor interface{}) *MockOutboxStore_MarkSent_Call[K, E] {
+	return &MockOutboxStore_MarkSent_Call[K, E]{Call: _e.mock.On("MarkSent", ctx, ids, executor)}
+}
+
+func (_c *MockOutboxStore_MarkSent_Call[K, E]) Run(run func(ctx context.Context, ids []int64, executor E)) *MockOutboxStore_MarkSent_Call[K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockOutboxStore_MarkSent_Call[K, E]) Return(_a0 error) *MockOutboxStore_MarkSent_Call[K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxStore_MarkSent_Call[K, E]) RunAndReturn(run func(context.Context, []int64, E) error) *MockOutboxStore_MarkSent_Call[K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Park provides a mock function with given fields: ctx, ids, reason, executor
+func (_m *MockOutboxStore[K, E]) Park(ctx context.Context, ids []int64, reason string, executor E) error {
+	ret := _m.Called(ctx, ids, reason, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Park")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string, E) error); ok {
+		r0 = rf(ctx, ids, reason, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxStore_Park_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Park'
+type MockOutboxStore_Park_Call[K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// Park is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+//   - reason string
+//   - executor E
+func (_e *MockOutboxStore_Expecter[K, E]) Park(ctx interface{}, ids interface{}, reason interface{}, executor interface{}) *MockOutboxStore_Park_Call[K, E] {
+	return &MockOutboxStore_Park_Call[K, E]{Call: _e.mock.On("Park", ctx, ids, reason, executor)}
+}
+
+func (_c *MockOutboxStore_Park_Call[K, E]) Run(run func(ctx context.Context, ids []int64, reason string, executor E)) *MockOutboxStore_Park_Call[K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(string), args[3].(E))
+	})
+	return _c
+}
+
+func (_c *MockOutboxStore_Park_Call[K, E]) Return(_a0 error) *MockOutboxStore_Park_Call[K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxStore_Park_Call[K, E]) RunAndReturn(run func(context.Context, []int64, string, E) error) *MockOutboxStore_Park_Call[K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOutboxStore creates a new instance of MockOutboxStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutboxStore[K interface{}, E interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutboxStore[K, E] {
+	mock := &MockOutboxStore[K, E]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,188 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTFACommitter is an autogenerated mock type for the TFACommitter type
+type MockTFACommitter[E interface{}] struct {
+	mock.Mock
+}
+
+type MockTFACommitter_Expecter[E interface{}] struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTFACommitter[E]) EXPECT() *MockTFACommitter_Expecter[E] {
+	return &MockTFACommitter_Expecter[E]{mock: &_m.Mock}
+}
+
+// Begin provides a mock function with given fields: _a0
+func (_m *MockTFACommitter[E]) Begin(_a0 context.Context) (E, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 E
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (E, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) E); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(E)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTFACommitter_Begin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Begin'
+type MockTFACommitter_Begin_Call[E interface{}] struct {
+	*mock.Call
+}
+
+// Begin is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *MockTFACommitter_Expecter[E]) Begin(_a0 interface{}) *MockTFACommitter_Begin_Call[E] {
+	return &MockTFACommitter_Begin_Call[E]{Call: _e.mock.On("Begin", _a0)}
+}
+
+func (_c *MockTFACommitter_Begin_Call[E]) Run(run func(_a0 context.Context)) *MockTFACommitter_Begin_Call[E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockTFACommitter_Begin_Call[E]) Return(executor E, err error) *MockTFACommitter_Begin_Call[E] {
+	_c.Call.Return(executor, err)
+	return _c
+}
+
+func (_c *MockTFACommitter_Begin_Call[E]) RunAndReturn(run func(context.Context) (E, error)) *MockTFACommitter_Begin_Call[E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Commit provides a mock function with given fields: ctx, executor
+func (_m *MockTFACommitter[E]) Commit(ctx context.Context, executor E) error {
+	ret := _m.Called(ctx, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Commit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, E) error); ok {
+		r0 = rf(ctx, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTFACommitter_Commit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Commit'
+type MockTFACommitter_Commit_Call[E interface{}] struct {
+	*mock.Call
+}
+
+// Commit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - executor E
+func (_e *MockTFACommitter_Expecter[E]) Commit(ctx interface{}, executor interface{}) *MockTFACommitter_Commit_Call[E] {
+	return &MockTFACommitter_Commit_Call[E]{Call: _e.mock.On("Commit", ctx, executor)}
+}
+
+func (_c *MockTFACommitter_Commit_Call[E]) Run(run func(ctx context.Context, executor E)) *MockTFACommitter_Commit_Call[E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(E))
+	})
+	return _c
+}
+
+func (_c *MockTFACommitter_Commit_Call[E]) Return(_a0 error) *MockTFACommitter_Commit_Call[E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTFACommitter_Commit_Call[E]) RunAndReturn(run func(context.Context, E) error) *MockTFACommitter_Commit_Call[E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rollback provides a mock function with given fields: ctx, executor
+func (_m *MockTFACommitter[E]) Rollback(ctx context.Context, executor E) error {
+	ret := _m.Called(ctx, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rollback")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, E) error); ok {
+		r0 = rf(ctx, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTFACommitter_Rollback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rollback'
+type MockTFACommitter_Rollback_Call[E interface{}] struct {
+	*mock.Call
+}
+
+// Rollback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - executor E
+func (_e *MockTFACommitter_Expecter[E]) Rollback(ctx interface{}, executor interface{}) *MockTFACommitter_Rollback_Call[E] {
+	return &MockTFACommitter_Rollback_Call[E]{Call: _e.mock.On("Rollback", ctx, executor)}
+}
+
+func (_c *MockTFACommitter_Rollback_Call[E]) Run(run func(ctx context.Context, executor E)) *MockTFACommitter_Rollback_Call[E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(E))
+	})
+	return _c
+}
+
+func (_c *MockTFACommitter_Rollback_Call[E]) Return(_a0 error) *MockTFACommitter_Rollback_Call[E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTFACommitter_Rollback_Call[E]) RunAndReturn(run func(context.Context, E) error) *MockTFACommitter_Rollback_Call[E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTFACommitter creates a new instance of MockTFACommitter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTFACommitter[E interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTFACommitter[E] {
+	mock := &MockTFACommitter[E]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package repositories
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTransactionalProjectionStore is an autogenerated mock type for the TransactionalProjectionStore type
+type MockTransactionalProjectionStore[T interface{}, E interface{}] struct {
+	mock.Mock
+}
+
+type MockTransactionalProjectionStore_Expecter[T interface{}, E interface{}] struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTransactionalProjectionStore[T, E]) EXPECT() *MockTransactionalProjectionStore_Expecter[T, E] {
+	return &MockTransactionalProjectionStore_Expecter[T, E]{mock: &_m.Mock}
+}
+
+// Save provides a mock function with given fields: ctx, projection, executor
+func (_m *MockTransactionalProjectionStore[T, E]) Save(ctx context.Context, projection T, executor E) error {
+	ret := _m.Called(ctx, projection, executor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, T, E) error); ok {
+		r0 = rf(ctx, projection, executor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionalProjectionStore_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockTransactionalProjectionStore_Save_Call[T interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - projection T
+//   - executor E
+func (_e *MockTransactionalProjectionStore_Expecter[T, E]) Save(ctx interface{}, projection interface{}, executor interface{}) *MockTransactionalProjectionStore_Save_Call[T, E] {
+	return &MockTransactionalProjectionStore_Save_Call[T, E]{Call: _e.mock.On("Save", ctx, projection, executor)}
+}
+
+func (_c *MockTransactionalProjectionStore_Save_Call[T, E]) Run(run func(ctx context.Context, projection T, executor E)) *MockTransactionalProjectionStore_Save_Call[T, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(T), args[2].(E))
+	})
+	return _c
+}
+
+func (_c *MockTransactionalProjectionStore_Save_Call[T, E]) Return(_a0 error) *MockTransactionalProjectionStore_Save_Call[T, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionalProjectionStore_Save_Call[T, E]) RunAndReturn(run func(context.Context, T, E) error) *MockTransactionalProjectionStore_Save_Call[T, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTransactionalProjectionStore creates a new instance of MockTransactionalProjectionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTransactionalProjectionStore[T interface{}, E interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTransactionalProjectionStore[T, E] {
+	mock := &MockTransactionalProjectionStore[T, E]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
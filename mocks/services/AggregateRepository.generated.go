@@ -0,0 +1,241 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	entities "github.com/alex-fullstack/event-sourcingo/domain/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockAggregateRepository is an autogenerated mock type for the AggregateRepository type
+type MockAggregateRepository[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	mock.Mock
+}
+
+type MockAggregateRepository_Expecter[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAggregateRepository[T, S, P, K]) EXPECT() *MockAggregateRepository_Expecter[T, S, P, K] {
+	return &MockAggregateRepository_Expecter[T, S, P, K]{mock: &_m.Mock}
+}
+
+// Exists provides a mock function with given fields: ctx, id
+func (_m *MockAggregateRepository[T, S, P, K]) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAggregateRepository_Exists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exists'
+type MockAggregateRepository_Exists_Call[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	*mock.Call
+}
+
+// Exists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockAggregateRepository_Expecter[T, S, P, K]) Exists(ctx interface{}, id interface{}) *MockAggregateRepository_Exists_Call[T, S, P, K] {
+	return &MockAggregateRepository_Exists_Call[T, S, P, K]{Call: _e.mock.On("Exists", ctx, id)}
+}
+
+func (_c *MockAggregateRepository_Exists_Call[T, S, P, K]) Run(run func(ctx context.Context, id uuid.UUID)) *MockAggregateRepository_Exists_Call[T, S, P, K] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockAggregateRepository_Exists_Call[T, S, P, K]) Return(_a0 bool, _a1 error) *MockAggregateRepository_Exists_Call[T, S, P, K] {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAggregateRepository_Exists_Call[T, S, P, K]) RunAndReturn(run func(context.Context, uuid.UUID) (bool, error)) *MockAggregateRepository_Exists_Call[T, S, P, K] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Load provides a mock function with given fields: ctx, aggregate
+func (_m *MockAggregateRepository[T, S, P, K]) Load(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K]) error {
+	ret := _m.Called(ctx, aggregate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Load")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AggregateProvider[T, S, P, K]) error); ok {
+		r0 = rf(ctx, aggregate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAggregateRepository_Load_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Load'
+type MockAggregateRepository_Load_Call[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	*mock.Call
+}
+
+// Load is a helper method to define mock.On call
+//   - ctx context.Context
+//   - aggregate entities.AggregateProvider[T,S,P,K]
+func (_e *MockAggregateRepository_Expecter[T, S, P, K]) Load(ctx interface{}, aggregate interface{}) *MockAggregateRepository_Load_Call[T, S, P, K] {
+	return &MockAggregateRepository_Load_Call[T, S, P, K]{Call: _e.mock.On("Load", ctx, aggregate)}
+}
+
+func (_c *MockAggregateRepository_Load_Call[T, S, P, K]) Run(run func(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K])) *MockAggregateRepository_Load_Call[T, S, P, K] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.AggregateProvider[T, S, P, K]))
+	})
+	return _c
+}
+
+func (_c *MockAggregateRepository_Load_Call[T, S, P, K]) Return(_a0 error) *MockAggregateRepository_Load_Call[T, S, P, K] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAggregateRepository_Load_Call[T, S, P, K]) RunAndReturn(run func(context.Context, entities.AggregateProvider[T, S, P, K]) error) *MockAggregateRepository_Load_Call[T, S, P, K] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LoadAsOf provides a mock function with given fields: ctx, aggregate, asOf
+func (_m *MockAggregateRepository[T, S, P, K]) LoadAsOf(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], asOf time.Time) error {
+	ret := _m.Called(ctx, aggregate, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadAsOf")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AggregateProvider[T, S, P, K], time.Time) error); ok {
+		r0 = rf(ctx, aggregate, asOf)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAggregateRepository_LoadAsOf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadAsOf'
+type MockAggregateRepository_LoadAsOf_Call[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	*mock.Call
+}
+
+// LoadAsOf is a helper method to define mock.On call
+//   - ctx context.Context
+//   - aggregate entities.AggregateProvider[T,S,P,K]
+//   - asOf time.Time
+func (_e *MockAggregateRepository_Expecter[T, S, P, K]) LoadAsOf(ctx interface{}, aggregate interface{}, asOf interface{}) *MockAggregateRepository_LoadAsOf_Call[T, S, P, K] {
+	return &MockAggregateRepository_LoadAsOf_Call[T, S, P, K]{Call: _e.mock.On("LoadAsOf", ctx, aggregate, asOf)}
+}
+
+func (_c *MockAggregateRepository_LoadAsOf_Call[T, S, P, K]) Run(run func(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], asOf time.Time)) *MockAggregateRepository_LoadAsOf_Call[T, S, P, K] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.AggregateProvider[T, S, P, K]), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockAggregateRepository_LoadAsOf_Call[T, S, P, K]) Return(_a0 error) *MockAggregateRepository_LoadAsOf_Call[T, S, P, K] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAggregateRepository_LoadAsOf_Call[T, S, P, K]) RunAndReturn(run func(context.Context, entities.AggregateProvider[T, S, P, K], time.Time) error) *MockAggregateRepository_LoadAsOf_Call[T, S, P, K] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LoadAtVersion provides a mock function with given fields: ctx, aggregate, version
+func (_m *MockAggregateRepository[T, S, P, K]) LoadAtVersion(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], version int) error {
+	ret := _m.Called(ctx, aggregate, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadAtVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AggregateProvider[T, S, P, K], int) error); ok {
+		r0 = rf(ctx, aggregate, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAggregateRepository_LoadAtVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadAtVersion'
+type MockAggregateRepository_LoadAtVersion_Call[T interface{}, S interface{}, P interface{}, K interface{}] struct {
+	*mock.Call
+}
+
+// LoadAtVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - aggregate entities.AggregateProvider[T,S,P,K]
+//   - version int
+func (_e *MockAggregateRepository_Expecter[T, S, P, K]) LoadAtVersion(ctx interface{}, aggregate interface{}, version interface{}) *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K] {
+	return &MockAggregateRepository_LoadAtVersion_Call[T, S, P, K]{Call: _e.mock.On("LoadAtVersion", ctx, aggregate, version)}
+}
+
+func (_c *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K]) Run(run func(ctx context.Context, aggregate entities.AggregateProvider[T, S, P, K], version int)) *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(entities.AggregateProvider[T, S, P, K]), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K]) Return(_a0 error) *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K]) RunAndReturn(run func(context.Context, entities.AggregateProvider[T, S, P, K], int) error) *MockAggregateRepository_LoadAtVersion_Call[T, S, P, K] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAggregateRepository creates a new instance of MockAggregateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAggregateRepository[T interface{}, S interface{}, P interface{}, K interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAggregateRepository[T, S, P, K] {
+	mock := &MockAggregateRepository[T, S, P, K]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package services
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCommandPurger is an autogenerated mock type for the CommandPurger type
+type MockCommandPurger struct {
+	mock.Mock
+}
+
+type MockCommandPurger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCommandPurger) EXPECT() *MockCommandPurger_Expecter {
+	return &MockCommandPurger_Expecter{mock: &_m.Mock}
+}
+
+// Purge provides a mock function with given fields: ctx
+func (_m *MockCommandPurger) Purge(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCommandPurger_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type MockCommandPurger_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockCommandPurger_Expecter) Purge(ctx interface{}) *MockCommandPurger_Purge_Call {
+	return &MockCommandPurger_Purge_Call{Call: _e.mock.On("Purge", ctx)}
+}
+
+func (_c *MockCommandPurger_Purge_Call) Run(run func(ctx context.Context)) *MockCommandPurger_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCommandPurger_Purge_Call) Return(_a0 int64, _a1 error) *MockCommandPurger_Purge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCommandPurger_Purge_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockCommandPurger_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCommandPurger creates a new instance of MockCommandPurger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCommandPurger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCommandPurger {
+	mock := &MockCommandPurger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
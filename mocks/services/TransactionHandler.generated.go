@@ -26,6 +26,53 @@ func (_m *MockTransactionHandler[T, S, P, K, E]) EXPECT() *MockTransactionHandle
 	return &MockTransactionHandler_Expecter[T, S, P, K, E]{mock: &_m.Mock}
 }
 
+// CatchUp provides a mock function with given fields: ctx, providerFn
+func (_m *MockTransactionHandler[T, S, P, K, E]) CatchUp(ctx context.Context, providerFn func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error {
+	ret := _m.Called(ctx, providerFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CatchUp")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error); ok {
+		r0 = rf(ctx, providerFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionHandler_CatchUp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CatchUp'
+type MockTransactionHandler_CatchUp_Call[T interface{}, S interface{}, P interface{}, K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// CatchUp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - providerFn func(uuid.UUID) entities.AggregateProvider[T,S,P,K]
+func (_e *MockTransactionHandler_Expecter[T, S, P, K, E]) CatchUp(ctx interface{}, providerFn interface{}) *MockTransactionHandler_CatchUp_Call[T, S, P, K, E] {
+	return &MockTransactionHandler_CatchUp_Call[T, S, P, K, E]{Call: _e.mock.On("CatchUp", ctx, providerFn)}
+}
+
+func (_c *MockTransactionHandler_CatchUp_Call[T, S, P, K, E]) Run(run func(ctx context.Context, providerFn func(uuid.UUID) entities.AggregateProvider[T, S, P, K])) *MockTransactionHandler_CatchUp_Call[T, S, P, K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(uuid.UUID) entities.AggregateProvider[T, S, P, K]))
+	})
+	return _c
+}
+
+func (_c *MockTransactionHandler_CatchUp_Call[T, S, P, K, E]) Return(_a0 error) *MockTransactionHandler_CatchUp_Call[T, S, P, K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionHandler_CatchUp_Call[T, S, P, K, E]) RunAndReturn(run func(context.Context, func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error) *MockTransactionHandler_CatchUp_Call[T, S, P, K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Handle provides a mock function with given fields: ctx, transaction, providerFn
 func (_m *MockTransactionHandler[T, S, P, K, E]) Handle(ctx context.Context, transaction *transactions.Transaction, providerFn func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error {
 	ret := _m.Called(ctx, transaction, providerFn)
@@ -74,6 +121,54 @@ func (_c *MockTransactionHandler_Handle_Call[T, S, P, K, E]) RunAndReturn(run fu
 	return _c
 }
 
+// HandleTransaction provides a mock function with given fields: ctx, transaction, providerFn
+func (_m *MockTransactionHandler[T, S, P, K, E]) HandleTransaction(ctx context.Context, transaction *transactions.Transaction, providerFn func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error {
+	ret := _m.Called(ctx, transaction, providerFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transactions.Transaction, func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error); ok {
+		r0 = rf(ctx, transaction, providerFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTransactionHandler_HandleTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HandleTransaction'
+type MockTransactionHandler_HandleTransaction_Call[T interface{}, S interface{}, P interface{}, K interface{}, E interface{}] struct {
+	*mock.Call
+}
+
+// HandleTransaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transaction *transactions.Transaction
+//   - providerFn func(uuid.UUID) entities.AggregateProvider[T,S,P,K]
+func (_e *MockTransactionHandler_Expecter[T, S, P, K, E]) HandleTransaction(ctx interface{}, transaction interface{}, providerFn interface{}) *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E] {
+	return &MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E]{Call: _e.mock.On("HandleTransaction", ctx, transaction, providerFn)}
+}
+
+func (_c *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E]) Run(run func(ctx context.Context, transaction *transactions.Transaction, providerFn func(uuid.UUID) entities.AggregateProvider[T, S, P, K])) *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*transactions.Transaction), args[2].(func(uuid.UUID) entities.AggregateProvider[T, S, P, K]))
+	})
+	return _c
+}
+
+func (_c *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E]) Return(_a0 error) *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E] {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E]) RunAndReturn(run func(context.Context, *transactions.Transaction, func(uuid.UUID) entities.AggregateProvider[T, S, P, K]) error) *MockTransactionHandler_HandleTransaction_Call[T, S, P, K, E] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockTransactionHandler creates a new instance of MockTransactionHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockTransactionHandler[T interface{}, S interface{}, P interface{}, K interface{}, E interface{}](t interface {